@@ -0,0 +1,21 @@
+//go:build fixtures
+
+package main
+
+import (
+	"os"
+
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest/fake"
+)
+
+// fixtureFetcher serves pull requests from the fixture file GH_RR_FIXTURES
+// points at, when it is set.
+func fixtureFetcher() (pullrequest.Fetcher, bool, error) {
+	path := os.Getenv("GH_RR_FIXTURES")
+	if path == "" {
+		return nil, false, nil
+	}
+	fetcher, err := fake.Load(path)
+	return fetcher, true, err
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jinwoo1225/gh-rr/internal/model"
+)
+
+// version is bumped whenever the file format changes incompatibly;
+// files written with another version are ignored.
+const version = 1
+
+// Entry is the last successful result of a category.
+type Entry struct {
+	FetchedAt    time.Time                  `json:"fetchedAt"`
+	PullRequests []*model.GithubPullRequest `json:"pullRequests"`
+	// TotalCount is the number of matching pull requests, including ones never loaded.
+	TotalCount int `json:"totalCount"`
+	// Pages is the number of pages PullRequests spans.
+	Pages       int    `json:"pages"`
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor,omitempty"`
+}
+
+type file struct {
+	Version    int              `json:"version"`
+	Categories map[string]Entry `json:"categories"`
+}
+
+// Store keeps the last results of every category on disk.
+// It is not safe for concurrent use.
+type Store struct {
+	path string
+	data file
+}
+
+// Path returns the location of the cache file,
+// $XDG_CACHE_HOME/gh-rr/cache.json or ~/.cache/gh-rr/cache.json.
+func Path() string {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gh-rr", "cache.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cache", "gh-rr", "cache.json")
+}
+
+// New returns an empty store backed by path.
+func New(path string) *Store {
+	return &Store{
+		path: path,
+		data: file{Version: version, Categories: map[string]Entry{}},
+	}
+}
+
+// Open loads the store at path. A missing file or one written by another
+// version yields an empty store.
+func Open(path string) (*Store, error) {
+	s := New(path)
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache %s: %w", path, err)
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing cache %s: %w", path, err)
+	}
+	if f.Version == version && f.Categories != nil {
+		s.data = f
+	}
+	return s, nil
+}
+
+// Get returns the cached entry for key.
+func (s *Store) Get(key string) (Entry, bool) {
+	e, ok := s.data.Categories[key]
+	return e, ok
+}
+
+// Put replaces the cached entry for key.
+func (s *Store) Put(key string, e Entry) {
+	s.data.Categories[key] = e
+}
+
+// Save writes the store to disk, replacing the previous file atomically.
+func (s *Store) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("encoding cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("creating cache dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".cache-*.json")
+	if err != nil {
+		return fmt.Errorf("creating cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replacing cache file: %w", err)
+	}
+	return nil
+}
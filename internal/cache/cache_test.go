@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jinwoo1225/gh-rr/internal/model"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "cache.json")
+	fetchedAt := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	pullRequest := &model.GithubPullRequest{
+		PrNumber:                42,
+		RepositoryNameWithOwner: "acme/widgets",
+		Title:                   "Fix the widget",
+		AuthorSlug:              "octocat",
+		URL:                     "https://github.com/acme/widgets/pull/42",
+		CommentsCount:           3,
+		CreatedAt:               fetchedAt.Add(-time.Hour),
+		UpdatedAt:               fetchedAt.Add(-time.Minute),
+		Labels:                  []model.Label{{Name: "bug", Color: "d73a4a"}},
+		CheckStatus:             "SUCCESS",
+		Reviews:                 []model.Review{{Author: "hubot", State: "APPROVED"}},
+	}
+
+	s := New(path)
+	s.Put("review", Entry{FetchedAt: fetchedAt, PullRequests: []*model.GithubPullRequest{pullRequest}})
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	e, ok := loaded.Get("review")
+	if !ok {
+		t.Fatal("Get(review) missing after round trip")
+	}
+	if !e.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("FetchedAt = %v; want %v", e.FetchedAt, fetchedAt)
+	}
+	if len(e.PullRequests) != 1 || !reflect.DeepEqual(e.PullRequests[0], pullRequest) {
+		t.Errorf("PullRequests = %+v; want %+v", e.PullRequests, pullRequest)
+	}
+}
+
+func TestOpenMissing(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "cache.json"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, ok := s.Get("review"); ok {
+		t.Error("Get(review) found an entry in an empty store")
+	}
+}
+
+func TestOpenIgnoresOtherVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	content := `{"version": 0, "categories": {"review": {"pullRequests": []}}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, ok := s.Get("review"); ok {
+		t.Error("Get(review) returned an entry from an old cache version")
+	}
+}
@@ -0,0 +1,224 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+// DefaultTimeout bounds a single category fetch.
+const DefaultTimeout = 30 * time.Second
+
+// Category describes a single tab of pull requests in the TUI.
+type Category struct {
+	// Name is the tab title.
+	Name string
+	// Options are the structured search flags passed to gh search prs.
+	Options []pullrequest.FetchOption
+	// Query is an optional raw GitHub search query, e.g. "org:acme label:backend".
+	Query string
+	// Order positions the tab; lower values come first.
+	Order int
+	// Limit is the number of pull requests fetched per page; zero means pullrequest.DefaultPerPage.
+	Limit int
+}
+
+var registry = []Category{
+	{
+		Name: "Review Requests",
+		Options: []pullrequest.FetchOption{
+			pullrequest.StateOpen,
+			pullrequest.ReviewRequestedMe,
+			pullrequest.DraftFalse,
+			pullrequest.ArchivedFalse,
+			pullrequest.SortCreated,
+		},
+		Order: 10,
+	},
+	{
+		Name: "My PRs",
+		Options: []pullrequest.FetchOption{
+			pullrequest.StateOpen,
+			pullrequest.AuthorMe,
+			pullrequest.DraftFalse,
+			pullrequest.ArchivedFalse,
+			pullrequest.SortCreated,
+		},
+		Order: 20,
+	},
+	{
+		Name: "Draft PRs",
+		Options: []pullrequest.FetchOption{
+			pullrequest.StateOpen,
+			pullrequest.DraftTrue,
+			pullrequest.InvolvesMe,
+			pullrequest.ArchivedFalse,
+			pullrequest.SortCreated,
+		},
+		Order: 30,
+	},
+	{
+		Name: "Involved Open PRs",
+		Options: []pullrequest.FetchOption{
+			pullrequest.StateOpen,
+			pullrequest.InvolvesMe,
+			pullrequest.DraftFalse,
+			pullrequest.ArchivedFalse,
+			pullrequest.SortCreated,
+		},
+		Order: 40,
+	},
+}
+
+// Registry returns the built-in categories sorted by Order.
+func Registry() []Category {
+	return Sorted(registry)
+}
+
+// Sorted returns a copy of categories sorted by Order, keeping the given order for ties.
+func Sorted(categories []Category) []Category {
+	sorted := make([]Category, len(categories))
+	copy(sorted, categories)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Order < sorted[j].Order
+	})
+	return sorted
+}
+
+// Key identifies the category in the on-disk cache. It covers the search
+// itself, so editing a tab's query never shows results of the old search, and
+// its page size, as the cached page count and cursor depend on it.
+func (c Category) Key() string {
+	options := make([]string, len(c.Options))
+	for i, option := range c.Options {
+		options[i] = string(option)
+	}
+	return c.Name + "|" + strings.Join(options, " ") + "|" + c.Query + "|" + strconv.Itoa(c.Search().PageSize())
+}
+
+// Search returns the pull request search described by the category.
+func (c Category) Search() pullrequest.Query {
+	return pullrequest.Query{Options: c.Options, Keywords: c.Query, PerPage: c.Limit}
+}
+
+// Result is the outcome of fetching a category. Page holds every pull request
+// fetched so far and describes the last page included.
+type Result struct {
+	pullrequest.Page
+	// Pages is the number of pages fetched.
+	Pages int
+	Err   error
+}
+
+// FetchOne fetches the first pages of a category, giving up after timeout.
+// Cancelling ctx aborts the fetch and yields an error wrapping context.Canceled.
+func FetchOne(ctx context.Context, fetcher pullrequest.Fetcher, c Category, pages int, timeout time.Duration) Result {
+	return FetchRest(ctx, fetcher, c, Result{}, pages, timeout)
+}
+
+// FetchRest fetches the pages of a category following the ones in result
+// until it holds pages of them or there are no more, giving up after timeout.
+func FetchRest(ctx context.Context, fetcher pullrequest.Fetcher, c Category, result Result, pages int, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for result.Pages < max(pages, 1) && (result.Pages == 0 || result.HasNextPage) {
+		next, err := fetcher.Search(ctx, c.Search(), result.EndCursor)
+		if err != nil {
+			return Result{Err: contextErr(ctx, timeout, err)}
+		}
+		result.PullRequests = append(result.PullRequests, next.PullRequests...)
+		result.TotalCount = next.TotalCount
+		result.HasNextPage = next.HasNextPage
+		result.EndCursor = next.EndCursor
+		result.Pages++
+		if !next.HasNextPage {
+			break
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return Result{Err: contextErr(ctx, timeout, err)}
+	}
+	return result
+}
+
+// FetchPage fetches the page of a category that follows cursor, giving up after timeout.
+func FetchPage(ctx context.Context, fetcher pullrequest.Fetcher, c Category, cursor string, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	next, err := fetcher.Search(ctx, c.Search(), cursor)
+	if err != nil {
+		return Result{Err: contextErr(ctx, timeout, err)}
+	}
+	return Result{Page: *next, Pages: 1}
+}
+
+// contextErr prefers the reason ctx ended over err, as gh reports a killed
+// process rather than why it was killed.
+func contextErr(ctx context.Context, timeout time.Duration, err error) error {
+	ctxErr := ctx.Err()
+	switch {
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		return fmt.Errorf("timed out after %s: %w", timeout, ctxErr)
+	case ctxErr != nil:
+		return ctxErr
+	default:
+		return err
+	}
+}
+
+// FetchAll fetches the first page of every category and returns one result
+// per category, in the same order as categories. A BatchFetcher fetches them
+// in a single request; otherwise every category is fetched concurrently.
+// Fetching is bounded by timeout.
+func FetchAll(ctx context.Context, fetcher pullrequest.Fetcher, categories []Category, timeout time.Duration) []Result {
+	if batch, ok := fetcher.(pullrequest.BatchFetcher); ok {
+		return fetchBatch(ctx, batch, categories, timeout)
+	}
+
+	results := make([]Result, len(categories))
+
+	wg := sync.WaitGroup{}
+	for i, c := range categories {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = FetchOne(ctx, fetcher, c, 1, timeout)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func fetchBatch(ctx context.Context, fetcher pullrequest.BatchFetcher, categories []Category, timeout time.Duration) []Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	queries := make([]pullrequest.Query, len(categories))
+	for i, c := range categories {
+		queries[i] = c.Search()
+	}
+	pages, errs := fetcher.SearchAll(ctx, queries)
+
+	results := make([]Result, len(categories))
+	for i := range categories {
+		switch {
+		case ctx.Err() != nil:
+			results[i] = Result{Err: contextErr(ctx, timeout, ctx.Err())}
+		case errs[i] != nil:
+			results[i] = Result{Err: errs[i]}
+		default:
+			results[i] = Result{Page: *pages[i], Pages: 1}
+		}
+	}
+	return results
+}
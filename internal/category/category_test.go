@@ -0,0 +1,185 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+// fetcherFunc adapts a function to pullrequest.Fetcher.
+type fetcherFunc func(ctx context.Context, c Category, cursor string) (*pullrequest.Page, error)
+
+func (f fetcherFunc) Search(ctx context.Context, q pullrequest.Query, cursor string) (*pullrequest.Page, error) {
+	// The tests identify categories by name, which they pass as the query keywords.
+	return f(ctx, Category{Name: q.Keywords}, cursor)
+}
+
+func singlePage(pullRequests ...*model.GithubPullRequest) *pullrequest.Page {
+	return &pullrequest.Page{PullRequests: pullRequests, TotalCount: len(pullRequests)}
+}
+
+func testCategories(names ...string) []Category {
+	categories := make([]Category, len(names))
+	for i, name := range names {
+		categories[i] = Category{Name: name, Query: name, Order: i}
+	}
+	return categories
+}
+
+func TestRegistrySorted(t *testing.T) {
+	categories := Registry()
+	for i := 1; i < len(categories); i++ {
+		if categories[i-1].Order > categories[i].Order {
+			t.Errorf("Registry() not sorted: %q (%d) before %q (%d)",
+				categories[i-1].Name, categories[i-1].Order, categories[i].Name, categories[i].Order)
+		}
+	}
+}
+
+func TestKeyCoversPageSize(t *testing.T) {
+	c := Category{Name: "Backend", Query: "org:acme"}
+	if c.Key() != (Category{Name: "Backend", Query: "org:acme", Limit: pullrequest.DefaultPerPage}).Key() {
+		t.Error("Key() differs between the default limit and the same limit set explicitly")
+	}
+	if c.Key() == (Category{Name: "Backend", Query: "org:acme", Limit: 50}).Key() {
+		t.Error("Key() does not change with the limit")
+	}
+}
+
+func TestFetchAllKeepsCategoryOrder(t *testing.T) {
+	delays := map[string]time.Duration{"a": 30 * time.Millisecond, "b": 0, "c": 10 * time.Millisecond}
+	fetch := fetcherFunc(func(ctx context.Context, c Category, cursor string) (*pullrequest.Page, error) {
+		time.Sleep(delays[c.Name])
+		return singlePage(&model.GithubPullRequest{Title: c.Name}), nil
+	})
+
+	results := FetchAll(context.Background(), fetch, testCategories("a", "b", "c"), time.Second)
+	for i, name := range []string{"a", "b", "c"} {
+		if results[i].Err != nil {
+			t.Fatalf("results[%d].Err = %v", i, results[i].Err)
+		}
+		if got := results[i].PullRequests[0].Title; got != name {
+			t.Errorf("results[%d] = %q; want %q", i, got, name)
+		}
+	}
+}
+
+func TestFetchAllPerCategoryErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	fetch := fetcherFunc(func(ctx context.Context, c Category, cursor string) (*pullrequest.Page, error) {
+		if c.Name == "bad" {
+			return nil, errBoom
+		}
+		return singlePage(&model.GithubPullRequest{Title: c.Name}), nil
+	})
+
+	results := FetchAll(context.Background(), fetch, testCategories("good", "bad", "also good"), time.Second)
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("healthy categories failed: %v, %v", results[0].Err, results[2].Err)
+	}
+	if !errors.Is(results[1].Err, errBoom) {
+		t.Errorf("results[1].Err = %v; want %v", results[1].Err, errBoom)
+	}
+}
+
+var blockingFetch = fetcherFunc(func(ctx context.Context, c Category, cursor string) (*pullrequest.Page, error) {
+	<-ctx.Done()
+	return nil, errors.New("signal: killed")
+})
+
+func TestFetchAllTimeout(t *testing.T) {
+	results := FetchAll(context.Background(), blockingFetch, testCategories("slow"), 10*time.Millisecond)
+	if !errors.Is(results[0].Err, context.DeadlineExceeded) {
+		t.Errorf("Err = %v; want deadline exceeded", results[0].Err)
+	}
+}
+
+func TestFetchAllCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan []Result)
+	go func() {
+		done <- FetchAll(ctx, blockingFetch, testCategories("a", "b"), time.Minute)
+	}()
+	cancel()
+
+	select {
+	case results := <-done:
+		for i, result := range results {
+			if !errors.Is(result.Err, context.Canceled) {
+				t.Errorf("results[%d].Err = %v; want canceled", i, result.Err)
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("FetchAll did not return after cancel")
+	}
+}
+
+func TestFetchOneMergesPages(t *testing.T) {
+	fetch := fetcherFunc(func(ctx context.Context, c Category, cursor string) (*pullrequest.Page, error) {
+		page := 1
+		if cursor != "" {
+			page, _ = strconv.Atoi(cursor)
+		}
+		return &pullrequest.Page{
+			PullRequests: []*model.GithubPullRequest{{PrNumber: page}},
+			TotalCount:   5,
+			HasNextPage:  page < 5,
+			EndCursor:    strconv.Itoa(page + 1),
+		}, nil
+	})
+
+	result := FetchOne(context.Background(), fetch, Category{Name: "a"}, 3, time.Second)
+	if result.Err != nil {
+		t.Fatalf("Err = %v", result.Err)
+	}
+	if len(result.PullRequests) != 3 || result.PullRequests[2].PrNumber != 3 {
+		t.Errorf("PullRequests = %+v; want pages 1 to 3", result.PullRequests)
+	}
+	if result.Pages != 3 || !result.HasNextPage || result.EndCursor != "4" || result.TotalCount != 5 {
+		t.Errorf("Result = %+v; want 3 pages of 5 continuing at 4", result)
+	}
+}
+
+// batchFetcher records how many round trips it took to fetch every category.
+type batchFetcher struct {
+	fetcherFunc
+	calls int
+}
+
+func (f *batchFetcher) SearchAll(ctx context.Context, queries []pullrequest.Query) ([]*pullrequest.Page, []error) {
+	f.calls++
+	pages := make([]*pullrequest.Page, len(queries))
+	errs := make([]error, len(queries))
+	for i, q := range queries {
+		pages[i], errs[i] = f.fetcherFunc.Search(ctx, q, "")
+	}
+	return pages, errs
+}
+
+func TestFetchAllBatches(t *testing.T) {
+	fetcher := &batchFetcher{fetcherFunc: func(ctx context.Context, c Category, cursor string) (*pullrequest.Page, error) {
+		if c.Name == "bad" {
+			return nil, errors.New("boom")
+		}
+		return singlePage(&model.GithubPullRequest{Title: c.Name}), nil
+	}}
+
+	results := FetchAll(context.Background(), fetcher, testCategories("a", "bad", "c"), time.Second)
+	if fetcher.calls != 1 {
+		t.Errorf("SearchAll called %d times; want 1", fetcher.calls)
+	}
+	if results[0].Err != nil || results[0].PullRequests[0].Title != "a" {
+		t.Errorf("results[0] = %+v; want a", results[0])
+	}
+	if results[1].Err == nil {
+		t.Error("results[1].Err = nil; want error")
+	}
+	if results[2].Err != nil || results[2].PullRequests[0].Title != "c" {
+		t.Errorf("results[2] = %+v; want c", results[2])
+	}
+}
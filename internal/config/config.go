@@ -0,0 +1,201 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+	"github.com/jinwoo1225/gh-rr/internal/utils"
+)
+
+// Fetchers selectable with the fetcher setting.
+const (
+	// FetcherGraphQL fetches every tab with rich metadata in a single GraphQL request.
+	FetcherGraphQL = "graphql"
+	// FetcherSearch fetches every tab through the REST search API.
+	FetcherSearch = "search"
+)
+
+// Checkout modes selectable with the checkout setting.
+const (
+	// CheckoutBranch switches the clone of the repository to the pull request branch.
+	CheckoutBranch = "branch"
+	// CheckoutWorktree checks every pull request out in its own git worktree,
+	// leaving the main clone alone.
+	CheckoutWorktree = "worktree"
+)
+
+// customOrderBase places user-defined tabs after the built-in ones unless they set an order.
+const customOrderBase = 100
+
+// Config is the user configuration read from config.yml.
+type Config struct {
+	// Defaults controls whether the built-in tabs are shown. It defaults to true.
+	Defaults *bool `yaml:"defaults"`
+	// Fetcher selects how pull requests are fetched; it defaults to FetcherGraphQL.
+	Fetcher string `yaml:"fetcher"`
+	// Limit is the number of pull requests loaded per page for every tab.
+	Limit int `yaml:"limit"`
+	// Checkout selects how pull requests are checked out; it defaults to CheckoutBranch.
+	Checkout string `yaml:"checkout"`
+	// Clone configures where repositories are cloned and found.
+	Clone Clone `yaml:"clone"`
+	// Tabs are user-defined tabs rendered alongside or instead of the built-in ones.
+	Tabs []Tab `yaml:"tabs"`
+}
+
+// Tab is a user-defined category backed by a raw GitHub search query.
+type Tab struct {
+	Title string `yaml:"title"`
+	Query string `yaml:"query"`
+	Order *int   `yaml:"order"`
+	// Limit overrides Config.Limit for this tab.
+	Limit int `yaml:"limit"`
+}
+
+// Clone configures where repositories are cloned and found.
+type Clone struct {
+	// Layout is where a repository is cloned below BASE_DIR, with {host},
+	// {owner} and {repo} placeholders; it defaults to utils.DefaultLayout.
+	Layout string `yaml:"layout"`
+	// Paths maps owner/repo to an existing clone.
+	Paths map[string]string `yaml:"paths"`
+	// Roots are directories searched for existing clones by their remote URL.
+	Roots []string `yaml:"roots"`
+}
+
+// Path returns the location of the config file.
+// GH_RR_CONFIG takes precedence, then $XDG_CONFIG_HOME/gh-rr/config.yml, then ~/.config/gh-rr/config.yml.
+func Path() string {
+	if p := os.Getenv("GH_RR_CONFIG"); p != "" {
+		return p
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "gh-rr", "config.yml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh-rr", "config.yml")
+}
+
+// Load reads the config file at Path. A missing file yields an empty config.
+func Load() (*Config, error) {
+	return LoadFile(Path())
+}
+
+// LoadFile reads the config file at path. A missing file yields an empty config.
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	switch c.Fetcher {
+	case "", FetcherGraphQL, FetcherSearch:
+	default:
+		return fmt.Errorf("fetcher: must be %q or %q, got %q", FetcherGraphQL, FetcherSearch, c.Fetcher)
+	}
+	if err := validateLimit(c.Limit); err != nil {
+		return fmt.Errorf("limit: %w", err)
+	}
+	switch c.Checkout {
+	case "", CheckoutBranch, CheckoutWorktree:
+	default:
+		return fmt.Errorf("checkout: must be %q or %q, got %q", CheckoutBranch, CheckoutWorktree, c.Checkout)
+	}
+	if c.Clone.Layout != "" {
+		if err := utils.ValidateLayout(c.Clone.Layout); err != nil {
+			return fmt.Errorf("clone: %w", err)
+		}
+	}
+	for repository, dir := range c.Clone.Paths {
+		if owner, name, ok := strings.Cut(repository, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("clone: paths: %q is not owner/repo", repository)
+		}
+		if !filepath.IsAbs(utils.ExpandHome(dir)) {
+			return fmt.Errorf("clone: paths: %s: %q must be absolute or start with ~", repository, dir)
+		}
+	}
+	for _, root := range c.Clone.Roots {
+		if !filepath.IsAbs(utils.ExpandHome(root)) {
+			return fmt.Errorf("clone: roots: %q must be absolute or start with ~", root)
+		}
+	}
+	for i, tab := range c.Tabs {
+		if strings.TrimSpace(tab.Title) == "" {
+			return fmt.Errorf("tabs[%d]: title is required", i)
+		}
+		if strings.TrimSpace(tab.Query) == "" {
+			return fmt.Errorf("tabs[%d] %q: query is required", i, tab.Title)
+		}
+		if err := validateLimit(tab.Limit); err != nil {
+			return fmt.Errorf("tabs[%d] %q: limit: %w", i, tab.Title, err)
+		}
+	}
+	return nil
+}
+
+// validateLimit accepts page sizes up to pullrequest.MaxPerPage, where zero
+// leaves the default page size.
+func validateLimit(limit int) error {
+	if limit < 0 || limit > pullrequest.MaxPerPage {
+		return fmt.Errorf("must be between 0 (the default) and %d, got %d", pullrequest.MaxPerPage, limit)
+	}
+	return nil
+}
+
+// Categories merges the built-in categories with the user-defined tabs, sorted by order.
+// The built-in categories are kept when the config would otherwise leave no tabs at all.
+func (c *Config) Categories(builtin []category.Category) []category.Category {
+	categories := make([]category.Category, 0, len(builtin)+len(c.Tabs))
+	if c.Defaults == nil || *c.Defaults || len(c.Tabs) == 0 {
+		for _, b := range builtin {
+			b.Limit = c.Limit
+			categories = append(categories, b)
+		}
+	}
+
+	for i, tab := range c.Tabs {
+		order := customOrderBase + i*10
+		if tab.Order != nil {
+			order = *tab.Order
+		}
+		limit := c.Limit
+		if tab.Limit != 0 {
+			limit = tab.Limit
+		}
+		categories = append(categories, category.Category{
+			Name:  tab.Title,
+			Query: tab.Query,
+			Order: order,
+			Limit: limit,
+		})
+	}
+
+	return category.Sorted(categories)
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func categoryNames(categories []category.Category) []string {
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "missing.yml"))
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got := len(cfg.Categories(category.Registry())); got != len(category.Registry()) {
+		t.Errorf("len(Categories()) = %d; want %d", got, len(category.Registry()))
+	}
+}
+
+func TestLoadFileTabs(t *testing.T) {
+	path := writeConfig(t, `
+tabs:
+  - title: Backend
+    query: review-requested:@me org:acme label:backend
+  - title: Platform
+    query: team-review-requested:acme/platform
+    order: 15
+`)
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	got := categoryNames(cfg.Categories(category.Registry()))
+	want := []string{"Review Requests", "Platform", "My PRs", "Draft PRs", "Involved Open PRs", "Backend"}
+	if len(got) != len(want) {
+		t.Fatalf("Categories() = %v; want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Categories()[%d] = %q; want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadFileWithoutDefaults(t *testing.T) {
+	path := writeConfig(t, `
+defaults: false
+tabs:
+  - title: Backend
+    query: review-requested:@me org:acme
+`)
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	categories := cfg.Categories(category.Registry())
+	if len(categories) != 1 {
+		t.Fatalf("Categories() = %v; want only Backend", categoryNames(categories))
+	}
+	if categories[0].Query != "review-requested:@me org:acme" {
+		t.Errorf("Categories()[0].Query = %q", categories[0].Query)
+	}
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	cases := map[string]string{
+		"missing title": "tabs:\n  - query: org:acme\n",
+		"missing query": "tabs:\n  - title: Acme\n",
+		"bad yaml":      "tabs: [",
+		"limit too big": "limit: 500\n",
+		"fetcher":       "fetcher: rest\n",
+		"checkout":      "checkout: clone\n",
+		"clone layout":  "clone:\n  layout: ../{owner}\n",
+		"clone path":    "clone:\n  paths:\n    widgets: ~/widgets\n",
+		"relative path": "clone:\n  paths:\n    acme/widgets: code/widgets\n",
+		"relative root": "clone:\n  roots:\n    - src\n",
+		"tab limit":     "tabs:\n  - title: Acme\n    query: org:acme\n    limit: -1\n",
+	}
+	for name, content := range cases {
+		if _, err := LoadFile(writeConfig(t, content)); err == nil {
+			t.Errorf("%s: LoadFile() error = nil; want error", name)
+		}
+	}
+}
+
+func TestLoadFileLimits(t *testing.T) {
+	path := writeConfig(t, `
+limit: 50
+tabs:
+  - title: Involved
+    query: involves:@me
+    limit: 100
+`)
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	for _, c := range cfg.Categories(category.Registry()) {
+		want := 50
+		if c.Name == "Involved" {
+			want = 100
+		}
+		if c.Limit != want {
+			t.Errorf("%s: Limit = %d; want %d", c.Name, c.Limit, want)
+		}
+	}
+}
+
+func TestLoadFileLimitMessage(t *testing.T) {
+	_, err := LoadFile(writeConfig(t, "limit: 500\n"))
+	if err == nil || !strings.Contains(err.Error(), "limit: must be between 0 (the default) and 100, got 500") {
+		t.Errorf("LoadFile() error = %v; want the allowed range", err)
+	}
+}
+
+func TestLoadFileClone(t *testing.T) {
+	path := writeConfig(t, `
+checkout: worktree
+clone:
+  layout: "{host}/{owner}/{repo}"
+  paths:
+    acme/widgets: ~/code/widgets
+  roots:
+    - ~/src
+`)
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if cfg.Checkout != CheckoutWorktree {
+		t.Errorf("Checkout = %q; want %q", cfg.Checkout, CheckoutWorktree)
+	}
+	if cfg.Clone.Layout != "{host}/{owner}/{repo}" || cfg.Clone.Paths["acme/widgets"] != "~/code/widgets" || len(cfg.Clone.Roots) != 1 {
+		t.Errorf("Clone = %+v", cfg.Clone)
+	}
+}
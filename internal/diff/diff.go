@@ -0,0 +1,140 @@
+// Package diff parses the unified diffs printed by gh pr diff.
+package diff
+
+import (
+	"strconv"
+	"strings"
+)
+
+// LineKind tells what a line of a file diff is.
+type LineKind int
+
+const (
+	Context LineKind = iota
+	Added
+	Removed
+	// HunkHeader is an "@@ -1,2 +1,3 @@" line starting a hunk.
+	HunkHeader
+)
+
+// Line is a line of a file diff.
+type Line struct {
+	Kind LineKind
+	// Text is the line without its +, - or space marker; hunk headers are kept whole.
+	Text string
+	// OldNumber and NewNumber are the line numbers in the old and new file,
+	// zero on the side the line does not exist on.
+	OldNumber int
+	NewNumber int
+}
+
+// File is the diff of a single file.
+type File struct {
+	// OldPath and NewPath are empty for added and deleted files respectively.
+	OldPath string
+	NewPath string
+	Binary  bool
+	Lines   []Line
+
+	Additions int
+	Deletions int
+}
+
+// Path is the path of the file after the change, or before it for deleted files.
+func (f File) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Parse splits a unified git diff into files. Lines it does not understand,
+// such as mode changes and index lines, are skipped.
+func Parse(text string) []File {
+	var files []File
+	var file *File
+	// oldLeft and newLeft count the lines remaining in the current hunk.
+	var oldLine, newLine, oldLeft, newLeft int
+
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		inHunk := oldLeft > 0 || newLeft > 0
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, File{})
+			file = &files[len(files)-1]
+			file.OldPath, file.NewPath = gitPaths(strings.TrimPrefix(line, "diff --git "))
+			oldLeft, newLeft = 0, 0
+		case file == nil:
+		case inHunk && (strings.HasPrefix(line, " ") || line == ""):
+			file.Lines = append(file.Lines, Line{Kind: Context, Text: strings.TrimPrefix(line, " "), OldNumber: oldLine, NewNumber: newLine})
+			oldLine++
+			newLine++
+			oldLeft--
+			newLeft--
+		case inHunk && strings.HasPrefix(line, "+"):
+			file.Lines = append(file.Lines, Line{Kind: Added, Text: line[1:], NewNumber: newLine})
+			file.Additions++
+			newLine++
+			newLeft--
+		case inHunk && strings.HasPrefix(line, "-"):
+			file.Lines = append(file.Lines, Line{Kind: Removed, Text: line[1:], OldNumber: oldLine})
+			file.Deletions++
+			oldLine++
+			oldLeft--
+		case strings.HasPrefix(line, "@@ "):
+			oldLine, oldLeft, newLine, newLeft = parseHunkHeader(line)
+			file.Lines = append(file.Lines, Line{Kind: HunkHeader, Text: line})
+		case strings.HasPrefix(line, "--- "):
+			file.OldPath = headerPath(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			file.NewPath = headerPath(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "new file mode"):
+			file.OldPath = ""
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.NewPath = ""
+		case strings.HasPrefix(line, "Binary files"):
+			file.Binary = true
+		}
+	}
+	return files
+}
+
+// gitPaths extracts the paths from the "a/old b/new" part of a diff --git line.
+func gitPaths(s string) (oldPath, newPath string) {
+	oldPath, newPath, ok := strings.Cut(s, " b/")
+	if !ok {
+		return "", ""
+	}
+	return strings.TrimPrefix(oldPath, "a/"), newPath
+}
+
+// headerPath returns the path of a ---/+++ line, or "" for /dev/null.
+func headerPath(s, prefix string) string {
+	s, _, _ = strings.Cut(s, "\t")
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, prefix)
+}
+
+// parseHunkHeader reads the start and length of both sides of "@@ -1,2 +3,4 @@".
+func parseHunkHeader(line string) (oldStart, oldCount, newStart, newCount int) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return 0, 0, 0, 0
+	}
+	oldStart, oldCount = parseRange(strings.TrimPrefix(fields[1], "-"))
+	newStart, newCount = parseRange(strings.TrimPrefix(fields[2], "+"))
+	return oldStart, oldCount, newStart, newCount
+}
+
+// parseRange reads "start,count" or "start", where count defaults to 1.
+func parseRange(s string) (start, count int) {
+	startStr, countStr, ok := strings.Cut(s, ",")
+	start, _ = strconv.Atoi(startStr)
+	count = 1
+	if ok {
+		count, _ = strconv.Atoi(countStr)
+	}
+	return start, count
+}
@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"testing"
+)
+
+const sample = `diff --git a/widget.go b/widget.go
+index 3b18e51..a9c4f2e 100644
+--- a/widget.go
++++ b/widget.go
+@@ -1,4 +1,5 @@
+ package widget
+ 
+-// Widget is a widget.
++// Widget is a configurable widget.
++// The zero value is ready to use.
+ type Widget struct{}
+diff --git a/docs/old.md b/docs/old.md
+deleted file mode 100644
+index 3b18e51..0000000
+--- a/docs/old.md
++++ /dev/null
+@@ -1 +0,0 @@
+--- old notes
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..a9c4f2e
+Binary files /dev/null and b/logo.png differ
+`
+
+func TestParse(t *testing.T) {
+	files := Parse(sample)
+	if len(files) != 3 {
+		t.Fatalf("len(files) = %d; want 3", len(files))
+	}
+
+	widget := files[0]
+	if widget.Path() != "widget.go" || widget.Additions != 2 || widget.Deletions != 1 {
+		t.Errorf("widget.go = %s +%d -%d; want widget.go +2 -1", widget.Path(), widget.Additions, widget.Deletions)
+	}
+	want := []Line{
+		{Kind: HunkHeader, Text: "@@ -1,4 +1,5 @@"},
+		{Kind: Context, Text: "package widget", OldNumber: 1, NewNumber: 1},
+		{Kind: Context, Text: "", OldNumber: 2, NewNumber: 2},
+		{Kind: Removed, Text: "// Widget is a widget.", OldNumber: 3},
+		{Kind: Added, Text: "// Widget is a configurable widget.", NewNumber: 3},
+		{Kind: Added, Text: "// The zero value is ready to use.", NewNumber: 4},
+		{Kind: Context, Text: "type Widget struct{}", OldNumber: 4, NewNumber: 5},
+	}
+	if len(widget.Lines) != len(want) {
+		t.Fatalf("widget.go has %d lines; want %d", len(widget.Lines), len(want))
+	}
+	for i, line := range widget.Lines {
+		if line != want[i] {
+			t.Errorf("line %d = %+v; want %+v", i, line, want[i])
+		}
+	}
+
+	deleted := files[1]
+	if deleted.NewPath != "" || deleted.Path() != "docs/old.md" || deleted.Deletions != 1 {
+		t.Errorf("deleted file = %+v", deleted)
+	}
+	if deleted.Lines[1].Text != "-- old notes" {
+		t.Errorf("removed line starting with -- = %q", deleted.Lines[1].Text)
+	}
+
+	binary := files[2]
+	if !binary.Binary || binary.OldPath != "" || binary.Path() != "logo.png" {
+		t.Errorf("binary file = %+v", binary)
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	if files := Parse(""); len(files) != 0 {
+		t.Errorf("Parse(\"\") = %+v; want no files", files)
+	}
+}
@@ -4,13 +4,117 @@ import (
 	"time"
 )
 
+// GithubPullRequest is a pull request as listed in a category.
+// The JSON form is persisted in the on-disk cache, so field names must stay stable.
+// Apart from IsDraft, Labels and Assignees, the fields after UpdatedAt are
+// only filled in by the GraphQL fetcher; the search fetcher sets Partial instead.
 type GithubPullRequest struct {
-	PrNumber                int
-	RepositoryNameWithOwner string
-	Title                   string
-	AuthorSlug              string
-	URL                     string
-	CommentsCount           int
-	CreatedAt               time.Time
-	UpdatedAt               time.Time
+	PrNumber                int       `json:"number"`
+	RepositoryNameWithOwner string    `json:"repository"`
+	Title                   string    `json:"title"`
+	AuthorSlug              string    `json:"author"`
+	URL                     string    `json:"url"`
+	CommentsCount           int       `json:"commentsCount"`
+	CreatedAt               time.Time `json:"createdAt"`
+	UpdatedAt               time.Time `json:"updatedAt"`
+
+	IsDraft      bool    `json:"isDraft,omitempty"`
+	HeadRefName  string  `json:"headRefName,omitempty"`
+	BaseRefName  string  `json:"baseRefName,omitempty"`
+	Additions    int     `json:"additions,omitempty"`
+	Deletions    int     `json:"deletions,omitempty"`
+	ChangedFiles int     `json:"changedFiles,omitempty"`
+	Labels       []Label `json:"labels,omitempty"`
+	// Assignees are the logins of the users the pull request is assigned to.
+	Assignees []string `json:"assignees,omitempty"`
+	// ReviewDecision is APPROVED, CHANGES_REQUESTED or REVIEW_REQUIRED, or empty
+	// when the repository does not require reviews.
+	ReviewDecision string `json:"reviewDecision,omitempty"`
+	// Mergeable is MERGEABLE, CONFLICTING or UNKNOWN.
+	Mergeable string `json:"mergeable,omitempty"`
+	// CheckStatus is the state of the head commit's status check rollup, e.g.
+	// SUCCESS, FAILURE or PENDING, or empty when it has no checks.
+	CheckStatus string `json:"checkStatus,omitempty"`
+	// FailingChecks are the names of the head commit's failed checks and statuses.
+	FailingChecks []string `json:"failingChecks,omitempty"`
+	// RequestedReviewers are the users (login) and teams (org/slug) asked for a review.
+	RequestedReviewers []string `json:"requestedReviewers,omitempty"`
+	// Reviews holds the latest review of every reviewer.
+	Reviews []Review `json:"reviews,omitempty"`
+	// Body is the Markdown description; it is only loaded for the detail view.
+	Body string `json:"body,omitempty"`
+	// Partial is set when the pull request was listed without its CI and review
+	// state, which the REST search API does not return.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// CheckState is the aggregate state of a pull request's checks.
+type CheckState string
+
+const (
+	CheckNone    CheckState = "none"
+	CheckPassing CheckState = "passing"
+	CheckFailing CheckState = "failing"
+	CheckPending CheckState = "pending"
+	// CheckUnknown is the state of a Partial pull request.
+	CheckUnknown CheckState = "unknown"
+)
+
+// CheckState aggregates CheckStatus into passing, failing, pending or none,
+// or unknown for a Partial pull request.
+func (p *GithubPullRequest) CheckState() CheckState {
+	if p.Partial {
+		return CheckUnknown
+	}
+	switch p.CheckStatus {
+	case "SUCCESS":
+		return CheckPassing
+	case "FAILURE", "ERROR":
+		return CheckFailing
+	case "PENDING", "EXPECTED":
+		return CheckPending
+	default:
+		return CheckNone
+	}
+}
+
+// Reviewer is a user or team asked for or leaving a review.
+type Reviewer struct {
+	Name string
+	// State is the state of the latest review, empty when none was left yet.
+	State string
+	// Requested is set while a review is requested, including a re-request after a review.
+	Requested bool
+}
+
+// Reviewers merges the requested reviewers with the latest reviews, listing
+// everyone who reviewed first and those yet to review after them.
+func (p *GithubPullRequest) Reviewers() []Reviewer {
+	reviewers := make([]Reviewer, 0, len(p.Reviews)+len(p.RequestedReviewers))
+	index := make(map[string]int, len(p.Reviews))
+	for _, review := range p.Reviews {
+		index[review.Author] = len(reviewers)
+		reviewers = append(reviewers, Reviewer{Name: review.Author, State: review.State})
+	}
+	for _, name := range p.RequestedReviewers {
+		if i, ok := index[name]; ok {
+			reviewers[i].Requested = true
+			continue
+		}
+		reviewers = append(reviewers, Reviewer{Name: name, Requested: true})
+	}
+	return reviewers
+}
+
+// Label is a pull request label; Color is a hex code without '#'.
+type Label struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Review is the latest review a user left on a pull request.
+type Review struct {
+	Author string `json:"author"`
+	// State is APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED or PENDING.
+	State string `json:"state"`
 }
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReviewers(t *testing.T) {
+	pullRequest := &GithubPullRequest{
+		RequestedReviewers: []string{"hubot", "acme/platform"},
+		Reviews: []Review{
+			{Author: "monalisa", State: "APPROVED"},
+			{Author: "hubot", State: "CHANGES_REQUESTED"},
+		},
+	}
+
+	want := []Reviewer{
+		{Name: "monalisa", State: "APPROVED"},
+		{Name: "hubot", State: "CHANGES_REQUESTED", Requested: true},
+		{Name: "acme/platform", Requested: true},
+	}
+	if got := pullRequest.Reviewers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Reviewers() = %+v; want %+v", got, want)
+	}
+}
+
+func TestCheckState(t *testing.T) {
+	cases := map[string]CheckState{
+		"SUCCESS":  CheckPassing,
+		"FAILURE":  CheckFailing,
+		"ERROR":    CheckFailing,
+		"PENDING":  CheckPending,
+		"EXPECTED": CheckPending,
+		"":         CheckNone,
+	}
+	for status, want := range cases {
+		pullRequest := &GithubPullRequest{CheckStatus: status}
+		if got := pullRequest.CheckState(); got != want {
+			t.Errorf("CheckState() with %q = %q; want %q", status, got, want)
+		}
+	}
+
+	partial := &GithubPullRequest{Partial: true}
+	if got := partial.CheckState(); got != CheckUnknown {
+		t.Errorf("CheckState() of a partial pull request = %q; want %q", got, CheckUnknown)
+	}
+}
@@ -0,0 +1,114 @@
+package pullrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2"
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jinwoo1225/gh-rr/internal/model"
+)
+
+// DetailFetcher is a Fetcher that can also load a single pull request with
+// everything the detail view shows, including its body.
+type DetailFetcher interface {
+	Fetcher
+	// PullRequest fetches pull request number of repository, given as "owner/repo".
+	PullRequest(ctx context.Context, repository string, number int) (*model.GithubPullRequest, error)
+}
+
+// pullRequestQuery fetches a single pull request by number.
+const pullRequestQuery = `
+query($owner: String!, $name: String!, $number: Int!) {
+	repository(owner: $owner, name: $name) {
+		pullRequest(number: $number) { ...pullRequestFields body }
+	}
+}
+` + pullRequestFieldsFragment
+
+type rawPullRequestResponse struct {
+	Repository *struct {
+		PullRequest *rawGraphQLPullRequest `json:"pullRequest"`
+	} `json:"repository"`
+}
+
+func (r *rawPullRequestResponse) pullRequest(repository string, number int) (*model.GithubPullRequest, error) {
+	if r.Repository == nil || r.Repository.PullRequest == nil {
+		return nil, fmt.Errorf("pull request %s#%d not found", repository, number)
+	}
+	return r.Repository.PullRequest.pullRequest(), nil
+}
+
+// splitRepository splits "owner/repo" into its owner and name.
+func splitRepository(repository string) (owner, name string, err error) {
+	owner, name, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid repository %q", repository)
+	}
+	return owner, name, nil
+}
+
+// ghActions implements what both fetchers do with a single pull request, for
+// both to embed.
+type ghActions struct {
+	// client runs GraphQL queries; without one they go through gh api graphql.
+	client *api.GraphQLClient
+}
+
+// PullRequest fetches a single pull request including its body.
+func (a ghActions) PullRequest(ctx context.Context, repository string, number int) (*model.GithubPullRequest, error) {
+	owner, name, err := splitRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]interface{}{"owner": owner, "name": name, "number": number}
+
+	var response rawPullRequestResponse
+	if err := a.graphQL(ctx, pullRequestQuery, variables, &response); err != nil {
+		return nil, err
+	}
+	return response.pullRequest(repository, number)
+}
+
+// graphQL runs query with variables and decodes its data into response,
+// through the GraphQL client if there is one and gh api graphql otherwise.
+func (a ghActions) graphQL(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	if a.client == nil {
+		return ghGraphQL(ctx, query, variables, response)
+	}
+	if err := a.client.DoWithContext(ctx, query, variables, response); err != nil {
+		return &FetchError{Err: err}
+	}
+	return nil
+}
+
+// ghGraphQL runs a GraphQL query through gh api graphql, for fetchers that
+// otherwise only use the gh command. Variables are strings or ints.
+func ghGraphQL(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	args := []string{"api", "graphql", "--raw-field", "query=" + query}
+	for name, value := range variables {
+		switch value := value.(type) {
+		case int:
+			args = append(args, "--field", name+"="+strconv.Itoa(value))
+		default:
+			args = append(args, "--raw-field", fmt.Sprintf("%s=%v", name, value))
+		}
+	}
+
+	stdout, stderr, err := gh.ExecContext(ctx, args...)
+	if err != nil {
+		return &FetchError{Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+
+	data := struct {
+		Data interface{} `json:"data"`
+	}{Data: response}
+	if err := json.NewDecoder(&stdout).Decode(&data); err != nil {
+		return fmt.Errorf("parsing GraphQL response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package pullrequest
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2"
+)
+
+// DiffFetcher is a Fetcher that can also load the diff of a pull request.
+type DiffFetcher interface {
+	Fetcher
+	// Diff fetches the unified diff of pull request number of repository.
+	Diff(ctx context.Context, repository string, number int) (string, error)
+}
+
+// Diff fetches the unified diff of a pull request with gh pr diff.
+func (ghActions) Diff(ctx context.Context, repository string, number int) (string, error) {
+	stdout, stderr, err := gh.ExecContext(ctx, "pr", "diff", strconv.Itoa(number), "--repo", repository, "--color", "never")
+	if err != nil {
+		return "", &FetchError{Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+	return stdout.String(), nil
+}
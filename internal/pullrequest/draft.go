@@ -0,0 +1,22 @@
+package pullrequest
+
+import (
+	"context"
+	"strconv"
+)
+
+// DraftToggler is a Fetcher that can also mark pull requests ready for review
+// and convert them back to drafts.
+type DraftToggler interface {
+	Fetcher
+	// SetDraft converts the pull request to a draft, or marks it ready for review when draft is false.
+	SetDraft(ctx context.Context, repository string, number int, draft bool) error
+}
+
+// SetDraft changes the draft state of a pull request with gh pr ready.
+func (ghActions) SetDraft(ctx context.Context, repository string, number int, draft bool) error {
+	if draft {
+		return runAction(ctx, "converting to draft", "pr", "ready", strconv.Itoa(number), "--repo", repository, "--undo")
+	}
+	return runAction(ctx, "marking ready for review", "pr", "ready", strconv.Itoa(number), "--repo", repository)
+}
@@ -0,0 +1,418 @@
+// Package fake provides a pullrequest.Fetcher that serves fixtures instead of
+// talking to GitHub, for tests and for running gh-rr offline.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+// Fixture is the canned response to a search query.
+type Fixture struct {
+	// Query is the search query as built by pullrequest.Query.String.
+	Query        string                     `json:"query"`
+	PullRequests []*model.GithubPullRequest `json:"pullRequests"`
+	// TotalCount defaults to the number of pull requests.
+	TotalCount int `json:"totalCount"`
+	// Error makes the search fail with a pullrequest.FetchError carrying Stderr.
+	Error  string `json:"error"`
+	Stderr string `json:"stderr"`
+}
+
+// Fetcher serves fixtures keyed by search query. Queries without a fixture
+// match no pull requests. It is safe for concurrent use.
+type Fetcher struct {
+	mu       sync.Mutex
+	fixtures map[string]Fixture
+	calls    map[string]int
+	// detailCalls counts PullRequest calls by "owner/repo#number".
+	detailCalls map[string]int
+	// diffs holds the diff of pull requests by "owner/repo#number".
+	diffs map[string]string
+	// mergeStatuses holds the merge status of pull requests by "owner/repo#number".
+	mergeStatuses map[string]*pullrequest.MergeStatus
+	// candidates holds the reviewer candidates of repositories.
+	candidates map[string][]pullrequest.Candidate
+	// labels and assignable hold the labels and assignable users of repositories.
+	labels     map[string][]model.Label
+	assignable map[string][]string
+	// timelines holds the conversation on pull requests by "owner/repo#number".
+	timelines map[string]pullrequest.Timeline
+	// actions records every change made; actionErr makes them fail.
+	actions   []Action
+	actionErr string
+}
+
+// Action is a change made to a pull request through the Fetcher.
+type Action struct {
+	// Name is the kind of change, e.g. "review".
+	Name       string
+	Repository string
+	Number     int
+	Args       []string
+}
+
+// New returns a Fetcher serving fixtures.
+func New(fixtures ...Fixture) *Fetcher {
+	f := &Fetcher{fixtures: map[string]Fixture{}, calls: map[string]int{}, detailCalls: map[string]int{}, diffs: map[string]string{},
+		mergeStatuses: map[string]*pullrequest.MergeStatus{}, candidates: map[string][]pullrequest.Candidate{},
+		labels: map[string][]model.Label{}, assignable: map[string][]string{}, timelines: map[string]pullrequest.Timeline{}}
+	for _, fixture := range fixtures {
+		f.Set(fixture)
+	}
+	return f
+}
+
+// Load returns a Fetcher serving the JSON array of fixtures in the file at path.
+func Load(path string) (*Fetcher, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixtures: %w", err)
+	}
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("parsing fixtures %s: %w", path, err)
+	}
+	return New(fixtures...), nil
+}
+
+// Set replaces the fixture for fixture.Query.
+func (f *Fetcher) Set(fixture Fixture) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fixtures[fixture.Query] = fixture
+}
+
+// Calls returns how many times q was searched.
+func (f *Fetcher) Calls(q pullrequest.Query) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[q.String()]
+}
+
+// Search serves the page of the fixture for q that follows cursor. Cursors are
+// offsets into the fixture's pull requests.
+func (f *Fetcher) Search(ctx context.Context, q pullrequest.Query, cursor string) (*pullrequest.Page, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	fixture := f.fixtures[q.String()]
+	f.calls[q.String()]++
+	f.mu.Unlock()
+
+	if fixture.Error != "" {
+		return nil, &pullrequest.FetchError{Err: errors.New(fixture.Error), Stderr: fixture.Stderr}
+	}
+
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil {
+			return nil, fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+	perPage := q.PerPage
+	if perPage <= 0 {
+		perPage = pullrequest.DefaultPerPage
+	}
+
+	start := min(offset, len(fixture.PullRequests))
+	end := min(start+perPage, len(fixture.PullRequests))
+	page := &pullrequest.Page{
+		PullRequests: fixture.PullRequests[start:end],
+		TotalCount:   max(fixture.TotalCount, len(fixture.PullRequests)),
+		HasNextPage:  end < len(fixture.PullRequests),
+	}
+	if page.HasNextPage {
+		page.EndCursor = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+// PullRequest serves the pull request number of repository from whichever
+// fixture lists it.
+func (f *Fetcher) PullRequest(ctx context.Context, repository string, number int) (*model.GithubPullRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.detailCalls[detailKey(repository, number)]++
+	for _, fixture := range f.fixtures {
+		for _, pullRequest := range fixture.PullRequests {
+			if pullRequest.RepositoryNameWithOwner == repository && pullRequest.PrNumber == number {
+				found := *pullRequest
+				return &found, nil
+			}
+		}
+	}
+	return nil, &pullrequest.FetchError{
+		Err:    errors.New("exit status 1"),
+		Stderr: fmt.Sprintf("GraphQL: Could not resolve to a PullRequest with the number of %d.", number),
+	}
+}
+
+// PullRequestCalls returns how many times the pull request number of repository was fetched.
+func (f *Fetcher) PullRequestCalls(repository string, number int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.detailCalls[detailKey(repository, number)]
+}
+
+// SetDiff sets the diff of pull request number of repository.
+func (f *Fetcher) SetDiff(repository string, number int, diff string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.diffs[detailKey(repository, number)] = diff
+}
+
+// Diff serves the diff set with SetDiff.
+func (f *Fetcher) Diff(ctx context.Context, repository string, number int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	diff, ok := f.diffs[detailKey(repository, number)]
+	if !ok {
+		return "", &pullrequest.FetchError{
+			Err:    errors.New("exit status 1"),
+			Stderr: fmt.Sprintf("no diff for %s#%d", repository, number),
+		}
+	}
+	return diff, nil
+}
+
+func detailKey(repository string, number int) string {
+	return repository + "#" + strconv.Itoa(number)
+}
+
+// Actions returns every change made so far.
+func (f *Fetcher) Actions() []Action {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Action(nil), f.actions...)
+}
+
+// FailActions makes every following change fail with stderr, or succeed again
+// when stderr is empty.
+func (f *Fetcher) FailActions(stderr string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.actionErr = stderr
+}
+
+// act records a change, failing it if FailActions says so.
+func (f *Fetcher) act(ctx context.Context, name, repository string, number int, args ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.actionErr != "" {
+		return &pullrequest.ActionError{Action: name, Err: errors.New("exit status 1"), Stderr: f.actionErr}
+	}
+	f.actions = append(f.actions, Action{Name: name, Repository: repository, Number: number, Args: args})
+	return nil
+}
+
+// SubmitReview records a review.
+func (f *Fetcher) SubmitReview(ctx context.Context, repository string, number int, event pullrequest.ReviewEvent, body string) error {
+	return f.act(ctx, "review", repository, number, string(event), body)
+}
+
+// SetMergeStatus sets the merge status of pull request number of repository.
+func (f *Fetcher) SetMergeStatus(repository string, number int, status *pullrequest.MergeStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.mergeStatuses[detailKey(repository, number)] = status
+}
+
+// MergeStatus serves the status set with SetMergeStatus; by default every
+// merge method is allowed and the pull request can be merged.
+func (f *Fetcher) MergeStatus(ctx context.Context, repository string, number int) (*pullrequest.MergeStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if status, ok := f.mergeStatuses[detailKey(repository, number)]; ok {
+		copied := *status
+		return &copied, nil
+	}
+	return &pullrequest.MergeStatus{
+		Methods:          []pullrequest.MergeMethod{pullrequest.MergeCommit, pullrequest.MergeSquash, pullrequest.MergeRebase},
+		AutoMergeAllowed: true,
+		Mergeable:        "MERGEABLE",
+		MergeStateStatus: "CLEAN",
+	}, nil
+}
+
+// Merge records a merge.
+func (f *Fetcher) Merge(ctx context.Context, repository string, number int, method pullrequest.MergeMethod) error {
+	return f.act(ctx, "merge", repository, number, string(method))
+}
+
+// SetAutoMerge records enabling or disabling auto-merge.
+func (f *Fetcher) SetAutoMerge(ctx context.Context, repository string, number int, method pullrequest.MergeMethod) error {
+	return f.act(ctx, "auto-merge", repository, number, string(method))
+}
+
+// UpdateBranch records a branch update.
+func (f *Fetcher) UpdateBranch(ctx context.Context, repository string, number int) error {
+	return f.act(ctx, "update-branch", repository, number)
+}
+
+// SetReviewerCandidates sets the collaborators and teams of repository.
+func (f *Fetcher) SetReviewerCandidates(repository string, candidates []pullrequest.Candidate) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.candidates[repository] = candidates
+}
+
+// ReviewerCandidates serves the candidates set with SetReviewerCandidates.
+func (f *Fetcher) ReviewerCandidates(ctx context.Context, repository string) ([]pullrequest.Candidate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]pullrequest.Candidate(nil), f.candidates[repository]...), nil
+}
+
+// RequestReviews records review requests.
+func (f *Fetcher) RequestReviews(ctx context.Context, repository string, number int, reviewers []string) error {
+	return f.act(ctx, "request-reviews", repository, number, reviewers...)
+}
+
+// RemoveReviewers records removed review requests.
+func (f *Fetcher) RemoveReviewers(ctx context.Context, repository string, number int, reviewers []string) error {
+	return f.act(ctx, "remove-reviewers", repository, number, reviewers...)
+}
+
+// SetDraft records a draft state change.
+func (f *Fetcher) SetDraft(ctx context.Context, repository string, number int, draft bool) error {
+	return f.act(ctx, "set-draft", repository, number, strconv.FormatBool(draft))
+}
+
+// SetLabels sets the labels defined in repository.
+func (f *Fetcher) SetLabels(repository string, labels []model.Label) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.labels[repository] = labels
+}
+
+// Labels serves the labels set with SetLabels.
+func (f *Fetcher) Labels(ctx context.Context, repository string) ([]model.Label, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]model.Label(nil), f.labels[repository]...), nil
+}
+
+// SetAssignableUsers sets the users that can be assigned in repository.
+func (f *Fetcher) SetAssignableUsers(repository string, logins []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.assignable[repository] = logins
+}
+
+// AssignableUsers serves the users set with SetAssignableUsers.
+func (f *Fetcher) AssignableUsers(ctx context.Context, repository string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.assignable[repository]...), nil
+}
+
+// EditLabels records a label change as "+added" and "-removed" arguments.
+func (f *Fetcher) EditLabels(ctx context.Context, repository string, number int, add, remove []string) error {
+	return f.act(ctx, "labels", repository, number, changeArgs(add, remove)...)
+}
+
+// EditAssignees records an assignee change as "+added" and "-removed" arguments.
+func (f *Fetcher) EditAssignees(ctx context.Context, repository string, number int, add, remove []string) error {
+	return f.act(ctx, "assignees", repository, number, changeArgs(add, remove)...)
+}
+
+func changeArgs(add, remove []string) []string {
+	var args []string
+	for _, value := range add {
+		args = append(args, "+"+value)
+	}
+	for _, value := range remove {
+		args = append(args, "-"+value)
+	}
+	return args
+}
+
+// SetTimeline sets the conversation on pull request number of repository.
+func (f *Fetcher) SetTimeline(repository string, number int, timeline pullrequest.Timeline) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.timelines[detailKey(repository, number)] = timeline
+}
+
+// Timeline serves the conversation set with SetTimeline.
+func (f *Fetcher) Timeline(ctx context.Context, repository string, number int) (*pullrequest.Timeline, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	timeline, ok := f.timelines[detailKey(repository, number)]
+	if !ok {
+		return nil, &pullrequest.FetchError{
+			Err:    errors.New("exit status 1"),
+			Stderr: fmt.Sprintf("no timeline for %s#%d", repository, number),
+		}
+	}
+	timeline.Items = append([]pullrequest.TimelineItem(nil), timeline.Items...)
+	return &timeline, nil
+}
+
+// AddComment records a comment.
+func (f *Fetcher) AddComment(ctx context.Context, repository string, number int, body string) error {
+	return f.act(ctx, "comment", repository, number, body)
+}
+
+// ReplyToThread records a reply; it is not tied to a pull request.
+func (f *Fetcher) ReplyToThread(ctx context.Context, threadID, body string) error {
+	return f.act(ctx, "reply", "", 0, threadID, body)
+}
+
+// SubmitReviewWithComments records a review, followed by every comment as
+// "path:line:side body", lines being "start-end" for ranges.
+func (f *Fetcher) SubmitReviewWithComments(ctx context.Context, repository string, number int, event pullrequest.ReviewEvent, body string, comments []pullrequest.DraftComment) error {
+	args := []string{string(event), body}
+	for _, comment := range comments {
+		lines := strconv.Itoa(comment.Line)
+		if comment.StartLine != 0 {
+			lines = fmt.Sprintf("%d-%d", comment.StartLine, comment.Line)
+		}
+		args = append(args, fmt.Sprintf("%s:%s:%s %s", comment.Path, lines, comment.Side, comment.Body))
+	}
+	return f.act(ctx, "review", repository, number, args...)
+}
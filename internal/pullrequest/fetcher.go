@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cli/go-gh/v2"
@@ -11,74 +14,246 @@ import (
 	"github.com/jinwoo1225/gh-rr/internal/model"
 )
 
+// FetchOption is a GitHub search qualifier such as "review-requested:@me".
 type FetchOption string
 
 const (
-	StateOpen         FetchOption = "--state=open"
-	AuthorMe          FetchOption = "--author=@me"
-	ReviewRequestedMe FetchOption = "--review-requested=@me"
-	DraftFalse        FetchOption = "--draft=false"
-	DraftTrue         FetchOption = "--draft=true"
-	ArchivedFalse     FetchOption = "--archived=false"
-	SortCreated       FetchOption = "--sort=created"
-	InvolvesMe        FetchOption = "--involves=@me"
+	StateOpen         FetchOption = "state:open"
+	AuthorMe          FetchOption = "author:@me"
+	ReviewRequestedMe FetchOption = "review-requested:@me"
+	DraftFalse        FetchOption = "draft:false"
+	DraftTrue         FetchOption = "draft:true"
+	ArchivedFalse     FetchOption = "archived:false"
+	SortCreated       FetchOption = "sort:created"
+	InvolvesMe        FetchOption = "involves:@me"
 )
 
 const (
-	outputJSONFormat = "--json=author,title,url,repository,createdAt,updatedAt,commentsCount,number"
+	// DefaultPerPage matches the page size gh search prs uses.
+	DefaultPerPage = 30
+	// MaxPerPage is the largest page the search API returns.
+	MaxPerPage = 100
+	// maxSearchResults is the number of results the search API serves per query.
+	maxSearchResults = 1000
+
+	sortPrefix = "sort:"
 )
 
+// Query describes a pull request search.
+type Query struct {
+	// Options are the structured qualifiers of the search.
+	Options []FetchOption
+	// Keywords is a raw GitHub search query, e.g. "org:acme label:backend".
+	Keywords string
+	// PerPage is the page size; zero means DefaultPerPage.
+	PerPage int
+}
+
+// String returns the search query sent to GitHub. Sort options are not part of it.
+func (q Query) String() string {
+	terms := []string{"is:pr"}
+	for _, option := range q.Options {
+		if !strings.HasPrefix(string(option), sortPrefix) {
+			terms = append(terms, string(option))
+		}
+	}
+	terms = append(terms, strings.Fields(q.Keywords)...)
+	return strings.Join(terms, " ")
+}
+
+func (q Query) sort() string {
+	for _, option := range q.Options {
+		if s, ok := strings.CutPrefix(string(option), sortPrefix); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// PageSize is the number of pull requests fetched per page: PerPage, or
+// DefaultPerPage when unset, capped at MaxPerPage.
+func (q Query) PageSize() int {
+	switch {
+	case q.PerPage <= 0:
+		return DefaultPerPage
+	case q.PerPage > MaxPerPage:
+		return MaxPerPage
+	default:
+		return q.PerPage
+	}
+}
+
+// Page is one page of search results.
+type Page struct {
+	PullRequests []*model.GithubPullRequest
+	// TotalCount is the number of pull requests matching the query, loaded or not.
+	TotalCount int
+	// HasNextPage reports whether another page can be fetched.
+	HasNextPage bool
+	// EndCursor is passed to Search to fetch the following page.
+	EndCursor string
+}
+
+// Fetcher searches pull requests.
+type Fetcher interface {
+	// Search fetches the page of q that follows cursor; an empty cursor
+	// fetches the first page.
+	Search(ctx context.Context, q Query, cursor string) (*Page, error)
+}
+
+// BatchFetcher is a Fetcher that can fetch the first page of several queries
+// in a single round trip.
+type BatchFetcher interface {
+	Fetcher
+	// SearchAll fetches the first page of every query and returns pages and
+	// errors in the same order as queries.
+	SearchAll(ctx context.Context, queries []Query) ([]*Page, []error)
+}
+
+// FetchError is returned when gh fails to search pull requests.
+// Stderr holds the message printed by gh, e.g. an expired token or a rate limit.
+type FetchError struct {
+	Err    error
+	Stderr string
+}
+
+func (e *FetchError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("fetching pull requests: %v", e.Err)
+	}
+	return fmt.Sprintf("fetching pull requests: %v: %s", e.Err, e.Stderr)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// ActionError is returned when gh fails to change a pull request.
+// Stderr holds the message printed by gh, e.g. a missing permission.
+type ActionError struct {
+	// Action describes what failed, e.g. "submitting review".
+	Action string
+	Err    error
+	Stderr string
+}
+
+func (e *ActionError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("%s: %v", e.Action, e.Err)
+	}
+	return fmt.Sprintf("%s: %v: %s", e.Action, e.Err, e.Stderr)
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
+
+// runAction runs a gh command changing a pull request, describing a failure with action.
+func runAction(ctx context.Context, action string, args ...string) error {
+	if _, stderr, err := gh.ExecContext(ctx, args...); err != nil {
+		return &ActionError{Action: action, Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+	return nil
+}
+
+type rawSearchResponse struct {
+	TotalCount int                                  `json:"total_count"`
+	Items      []*rawGithubPullRequestIssueResponse `json:"items"`
+}
+
 type rawGithubPullRequestIssueResponse struct {
-	Author struct {
-		Slug string `json:"login"`
-	} `json:"author"`
-	CommentsCount int       `json:"commentsCount"`
-	CreatedAt     time.Time `json:"createdAt"`
-	Number        int       `json:"number"`
-	Repository    struct {
-		NameWithOwner string `json:"nameWithOwner"`
-	} `json:"repository"`
-	Title     string    `json:"title"`
-	UpdatedAt time.Time `json:"updatedAt"`
-	Url       string    `json:"url"`
-}
-
-func Fetch(ctx context.Context, options ...FetchOption) ([]*model.GithubPullRequest, error) {
-	optionsStr := make([]string, 0, len(options)+3)
-	optionsStr = append(optionsStr, "search", "prs", outputJSONFormat)
-
-	for _, option := range options {
-		optionsStr = append(optionsStr, string(option))
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Comments      int           `json:"comments"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Number        int           `json:"number"`
+	RepositoryURL string        `json:"repository_url"`
+	Title         string        `json:"title"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	HTMLURL       string        `json:"html_url"`
+	Draft         bool          `json:"draft"`
+	Labels        []model.Label `json:"labels"`
+	Assignees     []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+}
+
+// SearchFetcher is the Fetcher backed by the REST search API through gh api.
+// Its cursors are page numbers. The search API returns no CI or review state,
+// so the pull requests it lists are Partial.
+type SearchFetcher struct {
+	ghActions
+}
+
+// Search fetches the page of pull requests matching q that follows cursor
+// through the search API, which also reports how many pull requests match in total.
+func (SearchFetcher) Search(ctx context.Context, q Query, cursor string) (*Page, error) {
+	page := 1
+	if cursor != "" {
+		var err error
+		if page, err = strconv.Atoi(cursor); err != nil || page < 1 {
+			return nil, fmt.Errorf("invalid page cursor %q", cursor)
+		}
 	}
 
-	stdout, stderr, err := gh.ExecContext(ctx, optionsStr...)
+	perPage := q.PageSize()
+	args := []string{
+		"api", "--method=GET", "search/issues",
+		"--raw-field", "q=" + q.String(),
+		"--raw-field", "per_page=" + strconv.Itoa(perPage),
+		"--raw-field", "page=" + strconv.Itoa(page),
+	}
+	if sort := q.sort(); sort != "" {
+		args = append(args, "--raw-field", "sort="+sort)
+	}
+
+	stdout, stderr, err := gh.ExecContext(ctx, args...)
 	if err != nil {
-		var errMsg string
-		if stderr.Len() > 0 {
-			errMsg = stderr.String()
-		}
-		return nil, fmt.Errorf("fetching pull requests: %w: %s", err, errMsg)
+		return nil, &FetchError{Err: err, Stderr: strings.TrimSpace(stderr.String())}
 	}
 
-	decoder := json.NewDecoder(&stdout)
-	var rawGithubPullRequestIssueResponses []*rawGithubPullRequestIssueResponse
-	if err := decoder.Decode(&rawGithubPullRequestIssueResponses); err != nil {
+	var response rawSearchResponse
+	if err := json.NewDecoder(&stdout).Decode(&response); err != nil {
 		return nil, fmt.Errorf("parsing pull requests: %w", err)
 	}
 
-	pullRequests := make([]*model.GithubPullRequest, 0, len(rawGithubPullRequestIssueResponses))
-	for _, rawPullRequestIssueResponse := range rawGithubPullRequestIssueResponses {
-		pullRequests = append(pullRequests, &model.GithubPullRequest{
-			PrNumber:                rawPullRequestIssueResponse.Number,
-			RepositoryNameWithOwner: rawPullRequestIssueResponse.Repository.NameWithOwner,
-			Title:                   rawPullRequestIssueResponse.Title,
-			AuthorSlug:              rawPullRequestIssueResponse.Author.Slug,
-			URL:                     rawPullRequestIssueResponse.Url,
-			CommentsCount:           rawPullRequestIssueResponse.CommentsCount,
-			CreatedAt:               rawPullRequestIssueResponse.CreatedAt,
-			UpdatedAt:               rawPullRequestIssueResponse.UpdatedAt,
-		})
+	pullRequests := make([]*model.GithubPullRequest, 0, len(response.Items))
+	for _, item := range response.Items {
+		pullRequest := &model.GithubPullRequest{
+			PrNumber:                item.Number,
+			RepositoryNameWithOwner: nameWithOwner(item.RepositoryURL),
+			Title:                   item.Title,
+			AuthorSlug:              item.User.Login,
+			URL:                     item.HTMLURL,
+			CommentsCount:           item.Comments,
+			CreatedAt:               item.CreatedAt,
+			UpdatedAt:               item.UpdatedAt,
+			IsDraft:                 item.Draft,
+			Labels:                  item.Labels,
+			Partial:                 true,
+		}
+		for _, assignee := range item.Assignees {
+			pullRequest.Assignees = append(pullRequest.Assignees, assignee.Login)
+		}
+		pullRequests = append(pullRequests, pullRequest)
+	}
+
+	reachable := min(response.TotalCount, maxSearchResults)
+	result := &Page{
+		PullRequests: pullRequests,
+		TotalCount:   response.TotalCount,
+		HasNextPage:  len(pullRequests) > 0 && page*perPage < reachable,
+	}
+	if result.HasNextPage {
+		result.EndCursor = strconv.Itoa(page + 1)
 	}
+	return result, nil
+}
 
-	return pullRequests, nil
+// nameWithOwner extracts "owner/repo" from a repository API URL such as
+// https://api.github.com/repos/owner/repo.
+func nameWithOwner(repositoryURL string) string {
+	dir, repo := path.Split(strings.TrimSuffix(repositoryURL, "/"))
+	return path.Base(dir) + "/" + repo
 }
@@ -0,0 +1,37 @@
+package pullrequest
+
+import "testing"
+
+func TestQueryString(t *testing.T) {
+	q := Query{
+		Options:  []FetchOption{StateOpen, ReviewRequestedMe, SortCreated},
+		Keywords: "  org:acme   -label:wip ",
+	}
+	if got, want := q.String(), "is:pr state:open review-requested:@me org:acme -label:wip"; got != want {
+		t.Errorf("String() = %q; want %q", got, want)
+	}
+	if got := q.sort(); got != "created" {
+		t.Errorf("sort() = %q; want %q", got, "created")
+	}
+}
+
+func TestQueryPerPage(t *testing.T) {
+	cases := map[int]int{0: DefaultPerPage, -1: DefaultPerPage, 50: 50, 500: MaxPerPage}
+	for perPage, want := range cases {
+		if got := (Query{PerPage: perPage}).PageSize(); got != want {
+			t.Errorf("PageSize() with PerPage=%d = %d; want %d", perPage, got, want)
+		}
+	}
+}
+
+func TestNameWithOwner(t *testing.T) {
+	cases := map[string]string{
+		"https://api.github.com/repos/acme/widgets":       "acme/widgets",
+		"https://ghe.example.com/api/v3/repos/acme/tools": "acme/tools",
+	}
+	for input, want := range cases {
+		if got := nameWithOwner(input); got != want {
+			t.Errorf("nameWithOwner(%q) = %q; want %q", input, got, want)
+		}
+	}
+}
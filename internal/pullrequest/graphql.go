@@ -0,0 +1,363 @@
+package pullrequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/jinwoo1225/gh-rr/internal/model"
+)
+
+// pullRequestFieldsFragment selects everything the TUI shows about a pull request.
+const pullRequestFieldsFragment = `
+fragment pullRequestFields on PullRequest {
+	number
+	title
+	url
+	isDraft
+	createdAt
+	updatedAt
+	additions
+	deletions
+	changedFiles
+	headRefName
+	baseRefName
+	reviewDecision
+	mergeable
+	author { login }
+	repository { nameWithOwner }
+	comments { totalCount }
+	labels(first: 20) { nodes { name color } }
+	assignees(first: 20) { nodes { login } }
+	reviewRequests(first: 20) {
+		nodes {
+			requestedReviewer {
+				... on User { login }
+				... on Team { combinedSlug }
+			}
+		}
+	}
+	latestReviews(first: 20) { nodes { author { login } state } }
+	commits(last: 1) {
+		nodes {
+			commit {
+				statusCheckRollup {
+					state
+					contexts(first: 50) {
+						nodes {
+							... on CheckRun { name conclusion }
+							... on StatusContext { context state }
+						}
+					}
+				}
+			}
+		}
+	}
+}
+`
+
+// pullRequestPageFragment selects a page of search results.
+const pullRequestPageFragment = `
+fragment pullRequestPage on SearchResultItemConnection {
+	issueCount
+	pageInfo { hasNextPage endCursor }
+	nodes {
+		... on PullRequest { ...pullRequestFields }
+	}
+}
+` + pullRequestFieldsFragment
+
+type rawSearchConnection struct {
+	IssueCount int `json:"issueCount"`
+	PageInfo   struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []*rawGraphQLPullRequest `json:"nodes"`
+}
+
+type rawGraphQLPullRequest struct {
+	Number         int       `json:"number"`
+	Title          string    `json:"title"`
+	URL            string    `json:"url"`
+	IsDraft        bool      `json:"isDraft"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+	Additions      int       `json:"additions"`
+	Deletions      int       `json:"deletions"`
+	ChangedFiles   int       `json:"changedFiles"`
+	HeadRefName    string    `json:"headRefName"`
+	BaseRefName    string    `json:"baseRefName"`
+	ReviewDecision string    `json:"reviewDecision"`
+	Mergeable      string    `json:"mergeable"`
+	Body           string    `json:"body"`
+	Author         struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+	Comments struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	Labels struct {
+		Nodes []model.Label `json:"nodes"`
+	} `json:"labels"`
+	Assignees struct {
+		Nodes []struct {
+			Login string `json:"login"`
+		} `json:"nodes"`
+	} `json:"assignees"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer struct {
+				Login        string `json:"login"`
+				CombinedSlug string `json:"combinedSlug"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	LatestReviews struct {
+		Nodes []struct {
+			Author struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			State string `json:"state"`
+		} `json:"nodes"`
+	} `json:"latestReviews"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State    string `json:"state"`
+					Contexts struct {
+						Nodes []rawCheckContext `json:"nodes"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// rawCheckContext is either a CheckRun (name, conclusion) or a StatusContext (context, state).
+type rawCheckContext struct {
+	Name       string `json:"name"`
+	Conclusion string `json:"conclusion"`
+	Context    string `json:"context"`
+	State      string `json:"state"`
+}
+
+// failingName returns the name of the check if it failed.
+func (c rawCheckContext) failingName() (string, bool) {
+	switch {
+	case c.Name != "":
+		switch c.Conclusion {
+		case "FAILURE", "TIMED_OUT", "CANCELLED", "ACTION_REQUIRED", "STARTUP_FAILURE":
+			return c.Name, true
+		}
+	case c.Context != "":
+		switch c.State {
+		case "FAILURE", "ERROR":
+			return c.Context, true
+		}
+	}
+	return "", false
+}
+
+// GraphQLFetcher is the Fetcher backed by the GraphQL API. Besides what the
+// search API returns, it fills in CI status, review state, labels and diff size,
+// and it fetches every category in a single request.
+type GraphQLFetcher struct {
+	ghActions
+}
+
+// NewGraphQLFetcher returns a GraphQLFetcher authenticated like gh.
+func NewGraphQLFetcher() (*GraphQLFetcher, error) {
+	client, err := api.DefaultGraphQLClient()
+	if err != nil {
+		return nil, fmt.Errorf("creating GraphQL client: %w", err)
+	}
+	return &GraphQLFetcher{ghActions{client: client}}, nil
+}
+
+// Search fetches the page of pull requests matching q that follows cursor.
+func (f *GraphQLFetcher) Search(ctx context.Context, q Query, cursor string) (*Page, error) {
+	pages, errs := f.search(ctx, []Query{q}, []string{cursor})
+	return pages[0], errs[0]
+}
+
+// SearchAll fetches the first page of every query in one request.
+func (f *GraphQLFetcher) SearchAll(ctx context.Context, queries []Query) ([]*Page, []error) {
+	return f.search(ctx, queries, make([]string, len(queries)))
+}
+
+func (f *GraphQLFetcher) search(ctx context.Context, queries []Query, cursors []string) ([]*Page, []error) {
+	pages := make([]*Page, len(queries))
+	errs := make([]error, len(queries))
+	if len(queries) == 0 {
+		return pages, errs
+	}
+
+	variables := make(map[string]interface{}, len(queries)*3)
+	for i, q := range queries {
+		variables["q"+strconv.Itoa(i)] = q.graphQLString()
+		variables["first"+strconv.Itoa(i)] = q.PageSize()
+		if cursors[i] != "" {
+			variables["after"+strconv.Itoa(i)] = cursors[i]
+		}
+	}
+
+	response := map[string]*rawSearchConnection{}
+	err := f.client.DoWithContext(ctx, searchQuery(len(queries)), variables, &response)
+
+	// A GraphQL error may concern a single search; the others still return data.
+	var gqlErr *api.GraphQLError
+	partial := errors.As(err, &gqlErr)
+	if err != nil && !partial {
+		for i := range errs {
+			errs[i] = &FetchError{Err: err}
+		}
+		return pages, errs
+	}
+	if partial {
+		for _, item := range gqlErr.Errors {
+			if i, ok := aliasIndex(item.Path); ok {
+				errs[i] = &FetchError{Err: fmt.Errorf("GraphQL: %s", item.Message)}
+			}
+		}
+	}
+
+	for i := range queries {
+		connection := response[alias(i)]
+		switch {
+		case errs[i] != nil:
+		case connection == nil && partial:
+			errs[i] = &FetchError{Err: gqlErr}
+		case connection == nil:
+			errs[i] = &FetchError{Err: fmt.Errorf("missing search result %s", alias(i))}
+		default:
+			pages[i] = connection.page()
+		}
+	}
+	return pages, errs
+}
+
+// graphQLString returns the search query including the sort order, which the
+// GraphQL search only accepts as a qualifier.
+func (q Query) graphQLString() string {
+	if sort := q.sort(); sort != "" {
+		return q.String() + " sort:" + sort + "-desc"
+	}
+	return q.String()
+}
+
+func alias(i int) string {
+	return "s" + strconv.Itoa(i)
+}
+
+// aliasIndex returns the index of the search an error path points into.
+func aliasIndex(path []interface{}) (int, bool) {
+	if len(path) == 0 {
+		return 0, false
+	}
+	name, ok := path[0].(string)
+	if !ok || !strings.HasPrefix(name, "s") {
+		return 0, false
+	}
+	i, err := strconv.Atoi(name[1:])
+	return i, err == nil
+}
+
+// searchQuery builds a query with one aliased search per category.
+func searchQuery(n int) string {
+	sb := strings.Builder{}
+	sb.WriteString("query(")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "$q%d: String!, $first%d: Int!, $after%d: String", i, i, i)
+	}
+	sb.WriteString(") {\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "\t%s: search(query: $q%d, type: ISSUE, first: $first%d, after: $after%d) { ...pullRequestPage }\n",
+			alias(i), i, i, i)
+	}
+	sb.WriteString("}\n")
+	sb.WriteString(pullRequestPageFragment)
+	return sb.String()
+}
+
+func (c *rawSearchConnection) page() *Page {
+	pullRequests := make([]*model.GithubPullRequest, 0, len(c.Nodes))
+	for _, node := range c.Nodes {
+		// Issues matching the query have no pull request fields.
+		if node == nil || node.Number == 0 {
+			continue
+		}
+		pullRequests = append(pullRequests, node.pullRequest())
+	}
+
+	page := &Page{
+		PullRequests: pullRequests,
+		TotalCount:   c.IssueCount,
+		HasNextPage:  c.PageInfo.HasNextPage,
+	}
+	if page.HasNextPage {
+		page.EndCursor = c.PageInfo.EndCursor
+	}
+	return page
+}
+
+func (r *rawGraphQLPullRequest) pullRequest() *model.GithubPullRequest {
+	pullRequest := &model.GithubPullRequest{
+		PrNumber:                r.Number,
+		RepositoryNameWithOwner: r.Repository.NameWithOwner,
+		Title:                   r.Title,
+		AuthorSlug:              r.Author.Login,
+		URL:                     r.URL,
+		CommentsCount:           r.Comments.TotalCount,
+		CreatedAt:               r.CreatedAt,
+		UpdatedAt:               r.UpdatedAt,
+		IsDraft:                 r.IsDraft,
+		HeadRefName:             r.HeadRefName,
+		BaseRefName:             r.BaseRefName,
+		Additions:               r.Additions,
+		Deletions:               r.Deletions,
+		ChangedFiles:            r.ChangedFiles,
+		Labels:                  r.Labels.Nodes,
+		ReviewDecision:          r.ReviewDecision,
+		Mergeable:               r.Mergeable,
+		Body:                    r.Body,
+	}
+
+	if len(r.Commits.Nodes) > 0 && r.Commits.Nodes[0].Commit.StatusCheckRollup != nil {
+		rollup := r.Commits.Nodes[0].Commit.StatusCheckRollup
+		pullRequest.CheckStatus = rollup.State
+		for _, check := range rollup.Contexts.Nodes {
+			if name, ok := check.failingName(); ok {
+				pullRequest.FailingChecks = append(pullRequest.FailingChecks, name)
+			}
+		}
+	}
+	for _, assignee := range r.Assignees.Nodes {
+		pullRequest.Assignees = append(pullRequest.Assignees, assignee.Login)
+	}
+	for _, node := range r.ReviewRequests.Nodes {
+		reviewer := node.RequestedReviewer
+		switch {
+		case reviewer.Login != "":
+			pullRequest.RequestedReviewers = append(pullRequest.RequestedReviewers, reviewer.Login)
+		case reviewer.CombinedSlug != "":
+			pullRequest.RequestedReviewers = append(pullRequest.RequestedReviewers, reviewer.CombinedSlug)
+		}
+	}
+	for _, node := range r.LatestReviews.Nodes {
+		pullRequest.Reviews = append(pullRequest.Reviews, model.Review{Author: node.Author.Login, State: node.State})
+	}
+	return pullRequest
+}
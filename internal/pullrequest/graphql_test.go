@@ -0,0 +1,158 @@
+package pullrequest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const searchResponseFixture = `{
+	"s0": {
+		"issueCount": 42,
+		"pageInfo": {"hasNextPage": true, "endCursor": "Y3Vyc29yOjMw"},
+		"nodes": [
+			{
+				"number": 7,
+				"title": "Add widgets",
+				"url": "https://github.com/acme/widgets/pull/7",
+				"isDraft": false,
+				"createdAt": "2025-05-01T10:00:00Z",
+				"updatedAt": "2025-05-02T10:00:00Z",
+				"additions": 120,
+				"deletions": 4,
+				"changedFiles": 3,
+				"headRefName": "feature/widgets",
+				"baseRefName": "main",
+				"reviewDecision": "REVIEW_REQUIRED",
+				"mergeable": "MERGEABLE",
+				"author": {"login": "octocat"},
+				"repository": {"nameWithOwner": "acme/widgets"},
+				"comments": {"totalCount": 2},
+				"labels": {"nodes": [{"name": "backend", "color": "0e8a16"}]},
+				"assignees": {"nodes": [{"login": "octocat"}]},
+				"reviewRequests": {"nodes": [
+					{"requestedReviewer": {"login": "hubot"}},
+					{"requestedReviewer": {"combinedSlug": "acme/platform"}}
+				]},
+				"latestReviews": {"nodes": [{"author": {"login": "monalisa"}, "state": "COMMENTED"}]},
+				"commits": {"nodes": [{"commit": {"statusCheckRollup": {
+					"state": "FAILURE",
+					"contexts": {"nodes": [
+						{"name": "lint", "conclusion": "SUCCESS"},
+						{"name": "test (ubuntu)", "conclusion": "FAILURE"},
+						{"context": "ci/jenkins", "state": "ERROR"},
+						{"context": "codecov", "state": "SUCCESS"}
+					]}
+				}}}]}
+			},
+			{}
+		]
+	}
+}`
+
+func TestSearchConnectionPage(t *testing.T) {
+	response := map[string]*rawSearchConnection{}
+	if err := json.Unmarshal([]byte(searchResponseFixture), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	page := response[alias(0)].page()
+	if page.TotalCount != 42 || !page.HasNextPage || page.EndCursor != "Y3Vyc29yOjMw" {
+		t.Errorf("page = %+v; want 42 results continuing at Y3Vyc29yOjMw", page)
+	}
+	if len(page.PullRequests) != 1 {
+		t.Fatalf("len(PullRequests) = %d; want 1, skipping the node without pull request fields", len(page.PullRequests))
+	}
+
+	pr := page.PullRequests[0]
+	if pr.RepositoryNameWithOwner != "acme/widgets" || pr.PrNumber != 7 || pr.AuthorSlug != "octocat" {
+		t.Errorf("pull request = %+v", pr)
+	}
+	if pr.CheckStatus != "FAILURE" || pr.ReviewDecision != "REVIEW_REQUIRED" || pr.Mergeable != "MERGEABLE" {
+		t.Errorf("status = %q/%q/%q", pr.CheckStatus, pr.ReviewDecision, pr.Mergeable)
+	}
+	if got := strings.Join(pr.FailingChecks, ","); got != "test (ubuntu),ci/jenkins" {
+		t.Errorf("FailingChecks = %q", got)
+	}
+	if got := strings.Join(pr.RequestedReviewers, ","); got != "hubot,acme/platform" {
+		t.Errorf("RequestedReviewers = %q", got)
+	}
+	if len(pr.Reviews) != 1 || pr.Reviews[0].Author != "monalisa" || pr.Reviews[0].State != "COMMENTED" {
+		t.Errorf("Reviews = %+v", pr.Reviews)
+	}
+	if len(pr.Labels) != 1 || pr.Labels[0].Name != "backend" {
+		t.Errorf("Labels = %+v", pr.Labels)
+	}
+	if got := strings.Join(pr.Assignees, ","); got != "octocat" {
+		t.Errorf("Assignees = %q", got)
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	query := searchQuery(2)
+	for _, want := range []string{
+		"$q0: String!, $first0: Int!, $after0: String, $q1: String!",
+		"s1: search(query: $q1, type: ISSUE, first: $first1, after: $after1) { ...pullRequestPage }",
+		"fragment pullRequestPage on SearchResultItemConnection",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("searchQuery(2) does not contain %q", want)
+		}
+	}
+}
+
+func TestAliasIndex(t *testing.T) {
+	cases := []struct {
+		path []interface{}
+		want int
+		ok   bool
+	}{
+		{[]interface{}{"s3", "nodes"}, 3, true},
+		{[]interface{}{"s0"}, 0, true},
+		{[]interface{}{"viewer"}, 0, false},
+		{nil, 0, false},
+	}
+	for _, c := range cases {
+		got, ok := aliasIndex(c.path)
+		if got != c.want || ok != c.ok {
+			t.Errorf("aliasIndex(%v) = %d, %v; want %d, %v", c.path, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestQueryGraphQLString(t *testing.T) {
+	q := Query{Options: []FetchOption{StateOpen, SortCreated}, Keywords: "org:acme"}
+	if got, want := q.graphQLString(), "is:pr state:open org:acme sort:created-desc"; got != want {
+		t.Errorf("graphQLString() = %q; want %q", got, want)
+	}
+}
+
+func TestPullRequestResponse(t *testing.T) {
+	var response rawPullRequestResponse
+	data := `{"repository": {"pullRequest": {"number": 7, "body": "Adds **widgets**", "headRefName": "feature/widgets", "baseRefName": "main"}}}`
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := response.pullRequest("acme/widgets", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Body != "Adds **widgets**" || pr.HeadRefName != "feature/widgets" || pr.BaseRefName != "main" {
+		t.Errorf("pull request = %+v", pr)
+	}
+
+	if _, err := (&rawPullRequestResponse{}).pullRequest("acme/widgets", 8); err == nil {
+		t.Error("missing pull request did not fail")
+	}
+}
+
+func TestSplitRepository(t *testing.T) {
+	if owner, name, err := splitRepository("acme/widgets"); err != nil || owner != "acme" || name != "widgets" {
+		t.Errorf("splitRepository(acme/widgets) = %q, %q, %v", owner, name, err)
+	}
+	for _, repository := range []string{"", "acme", "acme/", "/widgets", "acme/widgets/extra"} {
+		if _, _, err := splitRepository(repository); err == nil {
+			t.Errorf("splitRepository(%q) did not fail", repository)
+		}
+	}
+}
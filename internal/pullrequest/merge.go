@@ -0,0 +1,162 @@
+package pullrequest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// MergeMethod is a way of merging a pull request, named like the GraphQL PullRequestMergeMethod.
+type MergeMethod string
+
+const (
+	MergeCommit MergeMethod = "MERGE"
+	MergeSquash MergeMethod = "SQUASH"
+	MergeRebase MergeMethod = "REBASE"
+)
+
+// flag returns the gh pr merge flag selecting the method.
+func (m MergeMethod) flag() (string, error) {
+	switch m {
+	case MergeCommit:
+		return "--merge", nil
+	case MergeSquash:
+		return "--squash", nil
+	case MergeRebase:
+		return "--rebase", nil
+	default:
+		return "", fmt.Errorf("unknown merge method %q", m)
+	}
+}
+
+// MergeStatus is what merging a pull request depends on.
+type MergeStatus struct {
+	// Methods are the merge methods the repository allows, the default one first.
+	Methods          []MergeMethod
+	AutoMergeAllowed bool
+	// AutoMergeMethod is the method auto-merge will use, empty when it is disabled.
+	AutoMergeMethod MergeMethod
+	// Mergeable is MERGEABLE, CONFLICTING or UNKNOWN.
+	Mergeable string
+	// MergeStateStatus is e.g. CLEAN, BEHIND, BLOCKED, DIRTY or UNSTABLE.
+	MergeStateStatus string
+}
+
+// Merger is a Fetcher that can also merge pull requests.
+type Merger interface {
+	Fetcher
+	// MergeStatus fetches what merging pull request number of repository depends on.
+	MergeStatus(ctx context.Context, repository string, number int) (*MergeStatus, error)
+	// Merge merges the pull request with method.
+	Merge(ctx context.Context, repository string, number int, method MergeMethod) error
+	// SetAutoMerge enables auto-merge with method, or disables it when method is empty.
+	SetAutoMerge(ctx context.Context, repository string, number int, method MergeMethod) error
+	// UpdateBranch merges the base branch into the head branch of the pull request.
+	UpdateBranch(ctx context.Context, repository string, number int) error
+}
+
+// mergeStatusQuery fetches the merge settings of a repository and the merge state of one of its pull requests.
+const mergeStatusQuery = `
+query($owner: String!, $name: String!, $number: Int!) {
+	repository(owner: $owner, name: $name) {
+		mergeCommitAllowed
+		squashMergeAllowed
+		rebaseMergeAllowed
+		autoMergeAllowed
+		viewerDefaultMergeMethod
+		pullRequest(number: $number) {
+			mergeable
+			mergeStateStatus
+			autoMergeRequest { mergeMethod }
+		}
+	}
+}
+`
+
+type rawMergeStatusResponse struct {
+	Repository *struct {
+		MergeCommitAllowed       bool        `json:"mergeCommitAllowed"`
+		SquashMergeAllowed       bool        `json:"squashMergeAllowed"`
+		RebaseMergeAllowed       bool        `json:"rebaseMergeAllowed"`
+		AutoMergeAllowed         bool        `json:"autoMergeAllowed"`
+		ViewerDefaultMergeMethod MergeMethod `json:"viewerDefaultMergeMethod"`
+		PullRequest              *struct {
+			Mergeable        string `json:"mergeable"`
+			MergeStateStatus string `json:"mergeStateStatus"`
+			AutoMergeRequest *struct {
+				MergeMethod MergeMethod `json:"mergeMethod"`
+			} `json:"autoMergeRequest"`
+		} `json:"pullRequest"`
+	} `json:"repository"`
+}
+
+func (r *rawMergeStatusResponse) status(repository string, number int) (*MergeStatus, error) {
+	if r.Repository == nil || r.Repository.PullRequest == nil {
+		return nil, fmt.Errorf("pull request %s#%d not found", repository, number)
+	}
+	repo, pr := r.Repository, r.Repository.PullRequest
+
+	status := &MergeStatus{
+		AutoMergeAllowed: repo.AutoMergeAllowed,
+		Mergeable:        pr.Mergeable,
+		MergeStateStatus: pr.MergeStateStatus,
+	}
+	if pr.AutoMergeRequest != nil {
+		status.AutoMergeMethod = pr.AutoMergeRequest.MergeMethod
+	}
+	allowed := map[MergeMethod]bool{
+		MergeCommit: repo.MergeCommitAllowed,
+		MergeSquash: repo.SquashMergeAllowed,
+		MergeRebase: repo.RebaseMergeAllowed,
+	}
+	if allowed[repo.ViewerDefaultMergeMethod] {
+		status.Methods = append(status.Methods, repo.ViewerDefaultMergeMethod)
+	}
+	for _, method := range []MergeMethod{MergeCommit, MergeSquash, MergeRebase} {
+		if allowed[method] && method != repo.ViewerDefaultMergeMethod {
+			status.Methods = append(status.Methods, method)
+		}
+	}
+	return status, nil
+}
+
+// MergeStatus fetches the allowed merge methods and the merge state of a pull request.
+func (a ghActions) MergeStatus(ctx context.Context, repository string, number int) (*MergeStatus, error) {
+	owner, name, err := splitRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]interface{}{"owner": owner, "name": name, "number": number}
+
+	var response rawMergeStatusResponse
+	if err := a.graphQL(ctx, mergeStatusQuery, variables, &response); err != nil {
+		return nil, err
+	}
+	return response.status(repository, number)
+}
+
+// Merge merges a pull request with gh pr merge.
+func (ghActions) Merge(ctx context.Context, repository string, number int, method MergeMethod) error {
+	flag, err := method.flag()
+	if err != nil {
+		return err
+	}
+	return runAction(ctx, "merging", "pr", "merge", strconv.Itoa(number), "--repo", repository, flag)
+}
+
+// SetAutoMerge enables or disables auto-merge with gh pr merge.
+func (ghActions) SetAutoMerge(ctx context.Context, repository string, number int, method MergeMethod) error {
+	if method == "" {
+		return runAction(ctx, "disabling auto-merge", "pr", "merge", strconv.Itoa(number), "--repo", repository, "--disable-auto")
+	}
+	flag, err := method.flag()
+	if err != nil {
+		return err
+	}
+	return runAction(ctx, "enabling auto-merge", "pr", "merge", strconv.Itoa(number), "--repo", repository, "--auto", flag)
+}
+
+// UpdateBranch updates the head branch with gh pr update-branch.
+func (ghActions) UpdateBranch(ctx context.Context, repository string, number int) error {
+	return runAction(ctx, "updating branch", "pr", "update-branch", strconv.Itoa(number), "--repo", repository)
+}
@@ -0,0 +1,37 @@
+package pullrequest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMergeStatusResponse(t *testing.T) {
+	data := `{"repository": {
+		"mergeCommitAllowed": true,
+		"squashMergeAllowed": true,
+		"rebaseMergeAllowed": false,
+		"autoMergeAllowed": true,
+		"viewerDefaultMergeMethod": "SQUASH",
+		"pullRequest": {"mergeable": "MERGEABLE", "mergeStateStatus": "BEHIND", "autoMergeRequest": {"mergeMethod": "SQUASH"}}
+	}}`
+	var response rawMergeStatusResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := response.status("acme/widgets", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &MergeStatus{
+		Methods:          []MergeMethod{MergeSquash, MergeCommit},
+		AutoMergeAllowed: true,
+		AutoMergeMethod:  MergeSquash,
+		Mergeable:        "MERGEABLE",
+		MergeStateStatus: "BEHIND",
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("status = %+v; want %+v", status, want)
+	}
+}
@@ -0,0 +1,112 @@
+package pullrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ReviewEvent is the verdict of a review, named like the GraphQL PullRequestReviewEvent.
+type ReviewEvent string
+
+const (
+	ReviewApprove        ReviewEvent = "APPROVE"
+	ReviewComment        ReviewEvent = "COMMENT"
+	ReviewRequestChanges ReviewEvent = "REQUEST_CHANGES"
+)
+
+// ReviewSubmitter is a Fetcher that can also review pull requests.
+type ReviewSubmitter interface {
+	Fetcher
+	// SubmitReview reviews pull request number of repository with event and body.
+	// Comments and change requests need a body.
+	SubmitReview(ctx context.Context, repository string, number int, event ReviewEvent, body string) error
+}
+
+// SubmitReview reviews a pull request with gh pr review.
+func (ghActions) SubmitReview(ctx context.Context, repository string, number int, event ReviewEvent, body string) error {
+	args := []string{"pr", "review", strconv.Itoa(number), "--repo", repository}
+	switch event {
+	case ReviewApprove:
+		args = append(args, "--approve")
+	case ReviewComment:
+		args = append(args, "--comment")
+	case ReviewRequestChanges:
+		args = append(args, "--request-changes")
+	default:
+		return fmt.Errorf("unknown review event %q", event)
+	}
+	if body != "" {
+		args = append(args, "--body", body)
+	}
+
+	return runAction(ctx, "submitting review", args...)
+}
+
+// Sides of a diff a review comment is on.
+const (
+	// SideLeft is the old file, for removed lines.
+	SideLeft = "LEFT"
+	// SideRight is the new file, for added and unchanged lines.
+	SideRight = "RIGHT"
+)
+
+// DraftComment is a review comment on a line or range of lines of a file,
+// sent along with a review.
+type DraftComment struct {
+	Path string `json:"path"`
+	// Line is the last line commented on, StartLine the first one of a range or 0.
+	Line      int    `json:"line"`
+	Side      string `json:"side"`
+	StartLine int    `json:"start_line,omitempty"`
+	StartSide string `json:"start_side,omitempty"`
+	Body      string `json:"body"`
+}
+
+// ReviewCommenter is a ReviewSubmitter that can also comment on lines of the diff.
+type ReviewCommenter interface {
+	ReviewSubmitter
+	// SubmitReviewWithComments reviews the pull request like SubmitReview,
+	// adding comments anchored to lines of its diff.
+	SubmitReviewWithComments(ctx context.Context, repository string, number int, event ReviewEvent, body string, comments []DraftComment) error
+}
+
+// SubmitReviewWithComments creates a review with line comments through gh api.
+func (ghActions) SubmitReviewWithComments(ctx context.Context, repository string, number int, event ReviewEvent, body string, comments []DraftComment) error {
+	if _, _, err := splitRepository(repository); err != nil {
+		return err
+	}
+	switch event {
+	case ReviewApprove, ReviewComment, ReviewRequestChanges:
+	default:
+		return fmt.Errorf("unknown review event %q", event)
+	}
+
+	payload, err := json.Marshal(struct {
+		Event    ReviewEvent    `json:"event"`
+		Body     string         `json:"body,omitempty"`
+		Comments []DraftComment `json:"comments"`
+	}{event, body, comments})
+	if err != nil {
+		return err
+	}
+
+	// gh api reads a request body with --input from a file.
+	file, err := os.CreateTemp("", "gh-rr-review-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(payload)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("repos/%s/pulls/%d/reviews", repository, number)
+	return runAction(ctx, "submitting review", "api", "--method", "POST", endpoint, "--input", file.Name())
+}
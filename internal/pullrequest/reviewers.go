@@ -0,0 +1,112 @@
+package pullrequest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cli/go-gh/v2"
+)
+
+// Candidate is a user or team that can be asked for a review.
+type Candidate struct {
+	// Login is a user login, or "org/slug" for a team.
+	Login string
+	// Name is the display name of a team; users are shown by login.
+	Name string
+	Team bool
+}
+
+// ReviewerEditor is a Fetcher that can also change who is asked to review a pull request.
+type ReviewerEditor interface {
+	Fetcher
+	// ReviewerCandidates fetches the collaborators and teams of repository.
+	ReviewerCandidates(ctx context.Context, repository string) ([]Candidate, error)
+	// RequestReviews asks reviewers, user logins or "org/slug" teams, to review
+	// the pull request, again if they already did.
+	RequestReviews(ctx context.Context, repository string, number int, reviewers []string) error
+	// RemoveReviewers withdraws the review requests of reviewers.
+	RemoveReviewers(ctx context.Context, repository string, number int, reviewers []string) error
+}
+
+// ReviewerCandidates fetches the collaborators and teams of a repository with gh api.
+func (ghActions) ReviewerCandidates(ctx context.Context, repository string) ([]Candidate, error) {
+	owner, _, err := splitRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []struct {
+		Login string `json:"login"`
+	}
+	if err := ghAPIPages(ctx, "repos/"+repository+"/collaborators?per_page=100", &users); err != nil {
+		return nil, err
+	}
+	var teams []struct {
+		Slug string `json:"slug"`
+		Name string `json:"name"`
+	}
+	// Repositories owned by users have no teams and answer 404.
+	if err := ghAPIPages(ctx, "repos/"+repository+"/teams?per_page=100", &teams); err != nil {
+		if !isNotFound(err) {
+			return nil, err
+		}
+		teams = nil
+	}
+
+	candidates := make([]Candidate, 0, len(users)+len(teams))
+	for _, user := range users {
+		candidates = append(candidates, Candidate{Login: user.Login})
+	}
+	for _, team := range teams {
+		candidates = append(candidates, Candidate{Login: owner + "/" + team.Slug, Name: team.Name, Team: true})
+	}
+	return candidates, nil
+}
+
+// RequestReviews requests reviews with gh pr edit.
+func (ghActions) RequestReviews(ctx context.Context, repository string, number int, reviewers []string) error {
+	return editPullRequest(ctx, "requesting reviews", repository, number, "reviewer", reviewers, nil)
+}
+
+// RemoveReviewers removes review requests with gh pr edit.
+func (ghActions) RemoveReviewers(ctx context.Context, repository string, number int, reviewers []string) error {
+	return editPullRequest(ctx, "removing reviewers", repository, number, "reviewer", nil, reviewers)
+}
+
+// ghAPIPages fetches every page of a REST endpoint returning a JSON array
+// and appends the items to the slice items points to.
+func ghAPIPages[T any](ctx context.Context, endpoint string, items *[]T) error {
+	stdout, stderr, err := gh.ExecContext(ctx, "api", "--paginate", endpoint)
+	if err != nil {
+		return &FetchError{Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+
+	return decodePages(&stdout, endpoint, items)
+}
+
+// isNotFound reports whether err is gh api answering 404 Not Found.
+func isNotFound(err error) bool {
+	var fetchErr *FetchError
+	return errors.As(err, &fetchErr) && strings.Contains(fetchErr.Stderr, "(HTTP 404)")
+}
+
+// decodePages appends the items of every JSON array in r, as gh api
+// --paginate prints every page as a separate array.
+func decodePages[T any](r io.Reader, endpoint string, items *[]T) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var page []T
+		err := decoder.Decode(&page)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", endpoint, err)
+		}
+		*items = append(*items, page...)
+	}
+}
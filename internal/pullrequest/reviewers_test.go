@@ -0,0 +1,50 @@
+package pullrequest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodePages(t *testing.T) {
+	output := `[{"login": "alice"}, {"login": "bob"}]
+[{"login": "carol"}]
+`
+	var users []struct {
+		Login string `json:"login"`
+	}
+	if err := decodePages(strings.NewReader(output), "collaborators", &users); err != nil {
+		t.Fatal(err)
+	}
+
+	var logins []string
+	for _, user := range users {
+		logins = append(logins, user.Login)
+	}
+	if want := []string{"alice", "bob", "carol"}; !reflect.DeepEqual(logins, want) {
+		t.Errorf("logins = %v; want %v", logins, want)
+	}
+
+	if err := decodePages(strings.NewReader(`[{"login": `), "collaborators", &users); err == nil {
+		t.Error("decodePages() of truncated output succeeded; want an error")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{&FetchError{Err: errors.New("exit status 1"), Stderr: "gh: Not Found (HTTP 404)"}, true},
+		{fmt.Errorf("teams: %w", &FetchError{Err: errors.New("exit status 1"), Stderr: "gh: Not Found (HTTP 404)"}), true},
+		{&FetchError{Err: errors.New("exit status 1"), Stderr: "gh: Bad credentials (HTTP 401)"}, false},
+		{&FetchError{Err: errors.New("exit status 1"), Stderr: "gh: API rate limit exceeded (HTTP 403)"}, false},
+		{errors.New("error connecting to api.github.com"), false},
+	} {
+		if got := isNotFound(tc.err); got != tc.want {
+			t.Errorf("isNotFound(%v) = %v; want %v", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package pullrequest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TimelineKind is the kind of a timeline item.
+type TimelineKind string
+
+const (
+	TimelineDescription TimelineKind = "description"
+	TimelineComment     TimelineKind = "comment"
+	TimelineReview      TimelineKind = "review"
+	TimelineThread      TimelineKind = "thread"
+)
+
+// Comment is a comment of a review thread.
+type Comment struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// TimelineItem is an entry of the conversation on a pull request.
+type TimelineItem struct {
+	Kind      TimelineKind
+	Author    string
+	Body      string
+	CreatedAt time.Time
+	// State is the state of a review, e.g. APPROVED.
+	State string
+	// Thread is set for review threads.
+	Thread *Thread
+}
+
+// Thread is a review thread: comments on a line or range of a file.
+type Thread struct {
+	ID   string
+	Path string
+	// Line is the line the thread is on, StartLine the first line of a range.
+	// Both are 0 when the thread is outdated and no longer maps onto the diff.
+	Line      int
+	StartLine int
+	// Side is SideLeft or SideRight, the side of the diff Line is on.
+	Side string
+	// OriginalLine is the line in the diff the thread was started on.
+	OriginalLine int
+	Resolved     bool
+	Outdated     bool
+	Comments     []Comment
+}
+
+// Truncation tells that a pull request has more of something than its
+// timeline holds, as GitHub returns at most 100 at a time.
+type Truncation struct {
+	// What is counted, e.g. "comments".
+	What  string
+	Shown int
+	Total int
+}
+
+// Timeline is the conversation on a pull request.
+type Timeline struct {
+	// Items are the description, comments, reviews and review threads, oldest first.
+	Items []TimelineItem
+	// Truncated lists what Items holds only the first of.
+	Truncated []Truncation
+}
+
+// TimelineFetcher is a Fetcher that can also load the conversation on a pull
+// request and take part in it.
+type TimelineFetcher interface {
+	Fetcher
+	// Timeline fetches the description, comments, reviews and review threads
+	// of the pull request.
+	Timeline(ctx context.Context, repository string, number int) (*Timeline, error)
+	// AddComment posts a new comment on the pull request.
+	AddComment(ctx context.Context, repository string, number int, body string) error
+	// ReplyToThread replies to the review thread with threadID.
+	ReplyToThread(ctx context.Context, threadID, body string) error
+}
+
+// timelineQuery fetches the conversation on a pull request.
+const timelineQuery = `
+query($owner: String!, $name: String!, $number: Int!) {
+	repository(owner: $owner, name: $name) {
+		pullRequest(number: $number) {
+			author { login }
+			body
+			createdAt
+			comments(first: 100) { totalCount nodes { author { login } body createdAt } }
+			reviews(first: 100) { totalCount nodes { author { login } body state submittedAt } }
+			reviewThreads(first: 100) {
+				totalCount
+				nodes {
+					id
+					path
+					line
+					startLine
+					diffSide
+					originalLine
+					isResolved
+					isOutdated
+					comments(first: 100) { totalCount nodes { author { login } body createdAt } }
+				}
+			}
+		}
+	}
+}
+`
+
+// replyToThreadMutation replies to a review thread.
+const replyToThreadMutation = `
+mutation($threadId: ID!, $body: String!) {
+	addPullRequestReviewThreadReply(input: {pullRequestReviewThreadId: $threadId, body: $body}) {
+		comment { id }
+	}
+}
+`
+
+type rawAuthor struct {
+	Login string `json:"login"`
+}
+
+type rawComment struct {
+	Author    rawAuthor `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type rawComments struct {
+	TotalCount int          `json:"totalCount"`
+	Nodes      []rawComment `json:"nodes"`
+}
+
+type rawTimelineResponse struct {
+	Repository *struct {
+		PullRequest *struct {
+			Author    rawAuthor   `json:"author"`
+			Body      string      `json:"body"`
+			CreatedAt time.Time   `json:"createdAt"`
+			Comments  rawComments `json:"comments"`
+			Reviews   struct {
+				TotalCount int `json:"totalCount"`
+				Nodes      []struct {
+					Author      rawAuthor `json:"author"`
+					Body        string    `json:"body"`
+					State       string    `json:"state"`
+					SubmittedAt time.Time `json:"submittedAt"`
+				} `json:"nodes"`
+			} `json:"reviews"`
+			ReviewThreads struct {
+				TotalCount int `json:"totalCount"`
+				Nodes      []struct {
+					ID           string      `json:"id"`
+					Path         string      `json:"path"`
+					Line         int         `json:"line"`
+					StartLine    int         `json:"startLine"`
+					DiffSide     string      `json:"diffSide"`
+					OriginalLine int         `json:"originalLine"`
+					IsResolved   bool        `json:"isResolved"`
+					IsOutdated   bool        `json:"isOutdated"`
+					Comments     rawComments `json:"comments"`
+				} `json:"nodes"`
+			} `json:"reviewThreads"`
+		} `json:"pullRequest"`
+	} `json:"repository"`
+}
+
+// timeline merges the description, comments, reviews and threads in
+// chronological order. Reviews without a body that only carry thread
+// comments, and pending reviews, are left out.
+func (r *rawTimelineResponse) timeline(repository string, number int) (*Timeline, error) {
+	if r.Repository == nil || r.Repository.PullRequest == nil {
+		return nil, fmt.Errorf("pull request %s#%d not found", repository, number)
+	}
+	pr := r.Repository.PullRequest
+
+	timeline := &Timeline{}
+	truncated := func(what string, shown, total int) {
+		if shown < total {
+			timeline.Truncated = append(timeline.Truncated, Truncation{What: what, Shown: shown, Total: total})
+		}
+	}
+	truncated("comments", len(pr.Comments.Nodes), pr.Comments.TotalCount)
+	truncated("reviews", len(pr.Reviews.Nodes), pr.Reviews.TotalCount)
+	truncated("review threads", len(pr.ReviewThreads.Nodes), pr.ReviewThreads.TotalCount)
+
+	items := []TimelineItem{{Kind: TimelineDescription, Author: pr.Author.Login, Body: pr.Body, CreatedAt: pr.CreatedAt}}
+	for _, comment := range pr.Comments.Nodes {
+		items = append(items, TimelineItem{Kind: TimelineComment, Author: comment.Author.Login, Body: comment.Body, CreatedAt: comment.CreatedAt})
+	}
+	for _, review := range pr.Reviews.Nodes {
+		if review.State == "PENDING" || review.State == "COMMENTED" && review.Body == "" {
+			continue
+		}
+		items = append(items, TimelineItem{Kind: TimelineReview, Author: review.Author.Login, Body: review.Body, CreatedAt: review.SubmittedAt, State: review.State})
+	}
+	for _, node := range pr.ReviewThreads.Nodes {
+		if len(node.Comments.Nodes) == 0 {
+			continue
+		}
+		thread := &Thread{
+			ID:           node.ID,
+			Path:         node.Path,
+			Line:         node.Line,
+			StartLine:    node.StartLine,
+			Side:         node.DiffSide,
+			OriginalLine: node.OriginalLine,
+			Resolved:     node.IsResolved,
+			Outdated:     node.IsOutdated,
+		}
+		for _, comment := range node.Comments.Nodes {
+			thread.Comments = append(thread.Comments, Comment{Author: comment.Author.Login, Body: comment.Body, CreatedAt: comment.CreatedAt})
+		}
+		truncated("comments on "+node.Path, len(node.Comments.Nodes), node.Comments.TotalCount)
+		first := thread.Comments[0]
+		items = append(items, TimelineItem{Kind: TimelineThread, Author: first.Author, CreatedAt: first.CreatedAt, Thread: thread})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].CreatedAt.Before(items[j].CreatedAt) })
+	timeline.Items = items
+	return timeline, nil
+}
+
+// Timeline fetches the conversation on a pull request.
+func (a ghActions) Timeline(ctx context.Context, repository string, number int) (*Timeline, error) {
+	owner, name, err := splitRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]interface{}{"owner": owner, "name": name, "number": number}
+
+	var response rawTimelineResponse
+	if err := a.graphQL(ctx, timelineQuery, variables, &response); err != nil {
+		return nil, err
+	}
+	return response.timeline(repository, number)
+}
+
+// AddComment comments on a pull request with gh pr comment.
+func (ghActions) AddComment(ctx context.Context, repository string, number int, body string) error {
+	return runAction(ctx, "commenting", "pr", "comment", strconv.Itoa(number), "--repo", repository, "--body", body)
+}
+
+// ReplyToThread replies to a review thread with gh api graphql.
+func (ghActions) ReplyToThread(ctx context.Context, threadID, body string) error {
+	return runAction(ctx, "replying", "api", "graphql",
+		"--raw-field", "query="+replyToThreadMutation,
+		"--raw-field", "threadId="+threadID,
+		"--raw-field", "body="+body)
+}
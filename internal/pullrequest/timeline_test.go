@@ -0,0 +1,75 @@
+package pullrequest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTimelineResponse(t *testing.T) {
+	data := `{"repository": {"pullRequest": {
+		"author": {"login": "octocat"},
+		"body": "Adds widgets.",
+		"createdAt": "2025-05-01T10:00:00Z",
+		"comments": {"totalCount": 1, "nodes": [
+			{"author": {"login": "hubot"}, "body": "Deployed to staging.", "createdAt": "2025-05-03T10:00:00Z"}
+		]},
+		"reviews": {"totalCount": 3, "nodes": [
+			{"author": {"login": "monalisa"}, "body": "", "state": "COMMENTED", "submittedAt": "2025-05-02T10:00:00Z"},
+			{"author": {"login": "monalisa"}, "body": "Needs tests.", "state": "CHANGES_REQUESTED", "submittedAt": "2025-05-04T10:00:00Z"},
+			{"author": {"login": "octocat"}, "body": "", "state": "PENDING", "submittedAt": null}
+		]},
+		"reviewThreads": {"totalCount": 140, "nodes": [
+			{
+				"id": "PRRT_1", "path": "widget.go", "line": 12, "startLine": 10, "diffSide": "RIGHT", "originalLine": 12,
+				"isResolved": true, "isOutdated": false,
+				"comments": {"totalCount": 3, "nodes": [
+					{"author": {"login": "monalisa"}, "body": "Why?", "createdAt": "2025-05-02T10:00:00Z"},
+					{"author": {"login": "octocat"}, "body": "Fixed.", "createdAt": "2025-05-02T11:00:00Z"}
+				]}
+			}
+		]}
+	}}}`
+	var response rawTimelineResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	timeline, err := response.timeline("acme/widgets", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := timeline.Items
+	want := []TimelineKind{TimelineDescription, TimelineThread, TimelineComment, TimelineReview}
+	if len(items) != len(want) {
+		t.Fatalf("len(items) = %d; want %d, without the empty and pending reviews", len(items), len(want))
+	}
+	for i, kind := range want {
+		if items[i].Kind != kind {
+			t.Errorf("items[%d].Kind = %q; want %q", i, items[i].Kind, kind)
+		}
+	}
+
+	thread := items[1].Thread
+	if thread.ID != "PRRT_1" || thread.Path != "widget.go" || thread.StartLine != 10 || thread.Line != 12 || thread.Side != SideRight || !thread.Resolved {
+		t.Errorf("thread = %+v", thread)
+	}
+	if len(thread.Comments) != 2 || items[1].Author != "monalisa" {
+		t.Errorf("thread comments = %+v; want both, started by monalisa", thread.Comments)
+	}
+	if items[3].State != "CHANGES_REQUESTED" || items[3].Body != "Needs tests." {
+		t.Errorf("review = %+v", items[3])
+	}
+
+	wantTruncated := []Truncation{
+		{What: "review threads", Shown: 1, Total: 140},
+		{What: "comments on widget.go", Shown: 2, Total: 3},
+	}
+	if !reflect.DeepEqual(timeline.Truncated, wantTruncated) {
+		t.Errorf("Truncated = %+v; want %+v", timeline.Truncated, wantTruncated)
+	}
+
+	if _, err := (&rawTimelineResponse{}).timeline("acme/widgets", 7); err == nil {
+		t.Error("timeline() of a missing pull request succeeded; want an error")
+	}
+}
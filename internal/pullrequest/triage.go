@@ -0,0 +1,93 @@
+package pullrequest
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/jinwoo1225/gh-rr/internal/model"
+)
+
+// AssignMe assigns the authenticated user in EditAssignees.
+const AssignMe = "@me"
+
+// Triager is a Fetcher that can also change the labels and assignees of pull requests.
+type Triager interface {
+	Fetcher
+	// Labels fetches the labels defined in repository.
+	Labels(ctx context.Context, repository string) ([]model.Label, error)
+	// AssignableUsers fetches the logins of the users that can be assigned in repository.
+	AssignableUsers(ctx context.Context, repository string) ([]string, error)
+	// EditLabels adds and removes labels of the pull request.
+	EditLabels(ctx context.Context, repository string, number int, add, remove []string) error
+	// EditAssignees adds and removes assignees of the pull request, AssignMe
+	// being the authenticated user.
+	EditAssignees(ctx context.Context, repository string, number int, add, remove []string) error
+}
+
+// Labels fetches the labels of a repository with gh api.
+func (ghActions) Labels(ctx context.Context, repository string) ([]model.Label, error) {
+	if _, _, err := splitRepository(repository); err != nil {
+		return nil, err
+	}
+	var labels []model.Label
+	if err := ghAPIPages(ctx, "repos/"+repository+"/labels?per_page=100", &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// AssignableUsers fetches the assignable users of a repository with gh api.
+func (ghActions) AssignableUsers(ctx context.Context, repository string) ([]string, error) {
+	if _, _, err := splitRepository(repository); err != nil {
+		return nil, err
+	}
+	var users []struct {
+		Login string `json:"login"`
+	}
+	if err := ghAPIPages(ctx, "repos/"+repository+"/assignees?per_page=100", &users); err != nil {
+		return nil, err
+	}
+	logins := make([]string, 0, len(users))
+	for _, user := range users {
+		logins = append(logins, user.Login)
+	}
+	return logins, nil
+}
+
+// EditLabels changes labels with gh pr edit.
+func (ghActions) EditLabels(ctx context.Context, repository string, number int, add, remove []string) error {
+	return editPullRequest(ctx, "editing labels", repository, number, "label", add, remove)
+}
+
+// EditAssignees changes assignees with gh pr edit.
+func (ghActions) EditAssignees(ctx context.Context, repository string, number int, add, remove []string) error {
+	return editPullRequest(ctx, "editing assignees", repository, number, "assignee", add, remove)
+}
+
+// editPullRequest adds and removes values of a gh pr edit field such as label.
+func editPullRequest(ctx context.Context, action, repository string, number int, field string, add, remove []string) error {
+	return runAction(ctx, action, editArgs(repository, number, field, add, remove)...)
+}
+
+// editArgs returns the gh pr edit arguments adding and removing values of
+// field, one flag per value.
+func editArgs(repository string, number int, field string, add, remove []string) []string {
+	args := []string{"pr", "edit", strconv.Itoa(number), "--repo", repository}
+	for _, value := range add {
+		args = append(args, "--add-"+field, flagValue(value))
+	}
+	for _, value := range remove {
+		args = append(args, "--remove-"+field, flagValue(value))
+	}
+	return args
+}
+
+// flagValue quotes value for a gh list flag, which splits its values like a
+// CSV record, so that commas and quotes in names such as labels survive.
+func flagValue(value string) string {
+	if !strings.ContainsAny(value, ",\"\r\n") {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
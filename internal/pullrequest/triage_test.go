@@ -0,0 +1,32 @@
+package pullrequest
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEditArgs(t *testing.T) {
+	got := editArgs("acme/widgets", 7, "label", []string{"bug", "needs review, urgent"}, []string{`say "hi"`})
+	want := []string{
+		"pr", "edit", "7", "--repo", "acme/widgets",
+		"--add-label", "bug",
+		"--add-label", `"needs review, urgent"`,
+		"--remove-label", `"say ""hi"""`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("editArgs() = %q; want %q", got, want)
+	}
+
+	// gh reads every value of a list flag as a CSV record.
+	for i, label := range []string{"bug", "needs review, urgent", `say "hi"`} {
+		record, err := csv.NewReader(strings.NewReader(want[6+2*i])).Read()
+		if err != nil {
+			t.Fatalf("reading %q: %v", want[6+2*i], err)
+		}
+		if len(record) != 1 || record[0] != label {
+			t.Errorf("%q reads as %q; want [%q]", want[6+2*i], record, label)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jinwoo1225/gh-rr/internal/model"
+)
+
+var checkoutProgressStyle = lipgloss.NewStyle().
+	Padding(0, 1).
+	Border(lipgloss.NormalBorder()).
+	BorderForeground(lipgloss.Color("240")).
+	Foreground(lipgloss.Color("245"))
+
+// checkoutProgressLines is how many lines of command output the checkout dialog shows.
+const checkoutProgressLines = 8
+
+// stashedNotice tells how to get back the changes stashed to check a pull request out.
+const stashedNotice = "Your changes are stashed; press U on this pull request to switch back and restore them."
+
+// Checkouter clones repositories and checks pull requests out in them,
+// writing the output of the commands it runs to progress.
+type Checkouter interface {
+	// RepoDir returns where the repository is cloned and whether it is there
+	// already, failing when it cannot be cloned safely.
+	RepoDir(repository string) (string, bool, error)
+	// Prepare clones the repository if needed and returns the directory to
+	// check the pull request out in.
+	Prepare(ctx context.Context, repository string, number int, progress io.Writer) (string, error)
+	// DirtyFiles lists the uncommitted changes and untracked files of dir.
+	DirtyFiles(ctx context.Context, dir string) ([]string, error)
+	// CheckOut checks the pull request out in dir, stashing uncommitted changes first with stash.
+	CheckOut(ctx context.Context, dir, repository string, number int, stash bool, progress io.Writer) error
+	// Restore switches back to the branch the changes stashed by CheckOut
+	// were on and restores them, returning the directory they are in.
+	Restore(ctx context.Context, repository string, number int, progress io.Writer) (string, error)
+}
+
+// SetCheckouter enables checking pull requests out with c.
+func (m *ListModel) SetCheckouter(c Checkouter) {
+	m.checkouter = c
+}
+
+// checkoutStage is the step of a checkout the dialog is at.
+type checkoutStage int
+
+const (
+	checkoutConfirmClone checkoutStage = iota
+	checkoutPreparing
+	// checkoutDirty asks what to do with uncommitted changes.
+	checkoutDirty
+	checkoutRunning
+	checkoutDone
+	checkoutFailed
+)
+
+// checkoutProgressMsg is a line of output of the running checkout step.
+type checkoutProgressMsg struct {
+	line string
+	// overwrite replaces the previous line, which ended in a carriage return.
+	overwrite bool
+}
+
+// checkoutPreparedMsg reports the directory the pull request is checked out
+// in once the repository was cloned, with its uncommitted changes.
+type checkoutPreparedMsg struct {
+	dir     string
+	changes []string
+	err     error
+}
+
+// checkedOutMsg reports the outcome of checking the pull request out.
+type checkedOutMsg struct {
+	err error
+}
+
+// restoredMsg reports the outcome of restoring the changes stashed in dir.
+type restoredMsg struct {
+	dir string
+	err error
+}
+
+// shellExitedMsg reports that the shell started in dir after a checkout exited.
+type shellExitedMsg struct {
+	dir string
+	err error
+}
+
+// progressWriter sends the output of a command to the checkout dialog line by
+// line until ctx is done.
+type progressWriter struct {
+	ctx       context.Context
+	ch        chan<- tea.Msg
+	partial   []byte
+	overwrite bool
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		switch b {
+		case '\n', '\r':
+			w.flush()
+			// Progress meters redraw their line after a carriage return.
+			w.overwrite = b == '\r'
+		default:
+			w.partial = append(w.partial, b)
+		}
+	}
+	return len(p), nil
+}
+
+// flush sends the line written so far.
+func (w *progressWriter) flush() {
+	if len(w.partial) == 0 {
+		return
+	}
+	w.send(checkoutProgressMsg{line: string(w.partial), overwrite: w.overwrite})
+	w.partial = nil
+}
+
+func (w *progressWriter) send(msg tea.Msg) {
+	select {
+	case w.ch <- msg:
+	case <-w.ctx.Done():
+	}
+}
+
+// checkoutDialog clones the repository of a pull request and checks it out,
+// streaming the output of git and gh, then offers a shell in the checkout.
+type checkoutDialog struct {
+	entry      Entry
+	checkouter Checkouter
+	stage      checkoutStage
+	// dir is where the repository is cloned until it is prepared, then where
+	// the pull request is checked out.
+	dir      string
+	changes  []string
+	progress []string
+	err      error
+	// restoring switches back from the pull request and restores the stashed
+	// changes instead of checking it out.
+	restoring bool
+	// stashed is set once the changes were stashed to check the pull request out.
+	stashed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// messages carries the output and outcome of the running step.
+	messages chan tea.Msg
+}
+
+// openCheckout shows the dialog checking out the selected entry. It asks
+// before cloning a repository and otherwise starts right away.
+func (m *ListModel) openCheckout() tea.Cmd {
+	entry, selected := m.SelectedEntry()
+	if m.checkouter == nil || !selected {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	d := &checkoutDialog{entry: entry, checkouter: m.checkouter, ctx: ctx, cancel: cancel}
+	m.dialog = d
+	dir, cloned, err := m.checkouter.RepoDir(entry.RepositoryNameWithOwner)
+	d.dir = dir
+	switch {
+	case err != nil:
+		d.stage, d.err = checkoutFailed, err
+		return nil
+	case !cloned:
+		return nil
+	}
+	return d.prepare()
+}
+
+// openRestore shows the dialog switching back from the selected entry and
+// restoring the changes stashed to check it out.
+func (m *ListModel) openRestore() tea.Cmd {
+	entry, selected := m.SelectedEntry()
+	if m.checkouter == nil || !selected {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	d := &checkoutDialog{entry: entry, checkouter: m.checkouter, ctx: ctx, cancel: cancel, restoring: true}
+	m.dialog = d
+	d.stage = checkoutRunning
+	checkouter := m.checkouter
+	return d.start(func(ctx context.Context, progress io.Writer) tea.Msg {
+		dir, err := checkouter.Restore(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, progress)
+		return restoredMsg{dir: dir, err: err}
+	})
+}
+
+// start runs step in the background, streaming its output to the dialog and
+// sending the message it returns when it is done.
+func (d *checkoutDialog) start(step func(ctx context.Context, progress io.Writer) tea.Msg) tea.Cmd {
+	messages := make(chan tea.Msg, 16)
+	d.messages = messages
+	ctx := d.ctx
+	go func() {
+		defer close(messages)
+		writer := &progressWriter{ctx: ctx, ch: messages}
+		msg := step(ctx, writer)
+		writer.flush()
+		writer.send(msg)
+	}()
+	return d.next()
+}
+
+// next waits for the next message of the running step.
+func (d *checkoutDialog) next() tea.Cmd {
+	messages := d.messages
+	return func() tea.Msg {
+		msg, ok := <-messages
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// prepare clones the repository if needed and looks for uncommitted changes.
+func (d *checkoutDialog) prepare() tea.Cmd {
+	d.stage = checkoutPreparing
+	checkouter, entry := d.checkouter, d.entry
+	return d.start(func(ctx context.Context, progress io.Writer) tea.Msg {
+		dir, err := checkouter.Prepare(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, progress)
+		if err != nil {
+			return checkoutPreparedMsg{err: err}
+		}
+		changes, err := checkouter.DirtyFiles(ctx, dir)
+		return checkoutPreparedMsg{dir: dir, changes: changes, err: err}
+	})
+}
+
+// checkOut checks the pull request out, stashing uncommitted changes first with stash.
+func (d *checkoutDialog) checkOut(stash bool) tea.Cmd {
+	d.stage = checkoutRunning
+	d.stashed = stash
+	checkouter, entry, dir := d.checkouter, d.entry, d.dir
+	return d.start(func(ctx context.Context, progress io.Writer) tea.Msg {
+		err := checkouter.CheckOut(ctx, dir, entry.RepositoryNameWithOwner, entry.PrNumber, stash, progress)
+		return checkedOutMsg{err: err}
+	})
+}
+
+// close stops the running step and closes the dialog.
+func (d *checkoutDialog) close() (dialog, tea.Cmd) {
+	d.cancel()
+	return nil, nil
+}
+
+func (d *checkoutDialog) Update(msg tea.Msg) (dialog, tea.Cmd) {
+	switch msg := msg.(type) {
+	case checkoutProgressMsg:
+		if msg.overwrite && len(d.progress) > 0 {
+			d.progress[len(d.progress)-1] = msg.line
+		} else {
+			d.progress = append(d.progress, msg.line)
+		}
+		return d, d.next()
+	case checkoutPreparedMsg:
+		switch {
+		case msg.err != nil:
+			d.stage, d.err = checkoutFailed, msg.err
+			return d, nil
+		case len(msg.changes) > 0:
+			d.stage, d.dir, d.changes = checkoutDirty, msg.dir, msg.changes
+			return d, nil
+		}
+		d.dir = msg.dir
+		return d, d.checkOut(false)
+	case checkedOutMsg:
+		if msg.err != nil {
+			d.stage, d.err = checkoutFailed, msg.err
+			return d, nil
+		}
+		d.stage = checkoutDone
+		return d, nil
+	case restoredMsg:
+		if msg.err != nil {
+			d.stage, d.err = checkoutFailed, msg.err
+			return d, nil
+		}
+		d.stage, d.dir = checkoutDone, msg.dir
+		return d, nil
+	case tea.KeyMsg:
+		key := msg.String()
+		switch d.stage {
+		case checkoutConfirmClone:
+			switch key {
+			case "y", "enter":
+				return d, d.prepare()
+			case "n", "esc", "q":
+				return d.close()
+			}
+		case checkoutPreparing, checkoutRunning:
+			if key == "esc" {
+				return d.close()
+			}
+		case checkoutDirty:
+			switch key {
+			case "s":
+				return d, d.checkOut(true)
+			case "a":
+				return d, d.checkOut(false)
+			case "c", "n", "esc", "q":
+				return d.close()
+			}
+		case checkoutDone:
+			switch key {
+			case "s", "enter":
+				d.cancel()
+				return nil, shellCmd(d.dir)
+			case "esc", "q":
+				return d.close()
+			}
+		case checkoutFailed:
+			switch key {
+			case "enter", "esc", "q":
+				return d.close()
+			}
+		}
+	}
+	return d, nil
+}
+
+func (d *checkoutDialog) View() string {
+	verb := "Check out"
+	if d.restoring {
+		verb = "Switch back from"
+	}
+	lines := []string{
+		infoHeadingStyle.Render(fmt.Sprintf("%s %s#%d — %s", verb, d.entry.RepositoryNameWithOwner, d.entry.PrNumber, d.entry.Title)),
+		"",
+	}
+	if d.stage == checkoutConfirmClone {
+		lines = append(lines,
+			fmt.Sprintf("%s is not cloned yet.", d.entry.RepositoryNameWithOwner),
+			"",
+			infoHeadingStyle.Render(fmt.Sprintf("Clone it into %s? ", d.dir))+infoDimStyle.Render("y clone · n cancel"))
+		return dialogStyle.Render(strings.Join(lines, "\n"))
+	}
+
+	if len(d.progress) > 0 {
+		progress := d.progress[max(len(d.progress)-checkoutProgressLines, 0):]
+		lines = append(lines, checkoutProgressStyle.Render(strings.Join(progress, "\n")), "")
+	}
+	switch {
+	case d.stage == checkoutPreparing:
+		lines = append(lines, infoDimStyle.Render("Preparing the clone… esc cancel"))
+	case d.stage == checkoutRunning && d.restoring:
+		lines = append(lines, infoDimStyle.Render("Restoring your changes… esc cancel"))
+	case d.stage == checkoutRunning:
+		lines = append(lines, infoDimStyle.Render("Checking out… esc cancel"))
+	case d.stage == checkoutDirty:
+		lines = append(lines, fmt.Sprintf("%s has uncommitted changes:", d.dir))
+		for _, change := range d.changes[:min(len(d.changes), checkoutProgressLines)] {
+			lines = append(lines, "  "+change)
+		}
+		if len(d.changes) > checkoutProgressLines {
+			lines = append(lines, infoDimStyle.Render(fmt.Sprintf("  … and %d more", len(d.changes)-checkoutProgressLines)))
+		}
+		lines = append(lines, "", infoDimStyle.Render("s stash them · a continue anyway · c cancel"))
+	case d.stage == checkoutDone && d.restoring:
+		lines = append(lines,
+			checkStyles[model.CheckPassing].Render("✓ Restored your changes in "+d.dir),
+			"",
+			infoDimStyle.Render("s open a shell there · esc stay in the list"))
+	case d.stage == checkoutDone:
+		lines = append(lines, checkStyles[model.CheckPassing].Render(fmt.Sprintf("✓ Checked out #%d in %s", d.entry.PrNumber, d.dir)))
+		if d.stashed {
+			lines = append(lines, stashedNotice)
+		}
+		lines = append(lines, "", infoDimStyle.Render("s open a shell there · esc stay in the list"))
+	case d.stage == checkoutFailed:
+		lines = append(lines, checkStyles[model.CheckFailing].Render("⚠ "+errorDetail(d.err)))
+		// The changes stay stashed when checking out fails after stashing them.
+		if d.stashed {
+			lines = append(lines, stashedNotice)
+		}
+		lines = append(lines, "", infoDimStyle.Render("esc close"))
+	}
+	return dialogStyle.Render(strings.Join(lines, "\n"))
+}
+
+// shellCmd suspends the TUI for $SHELL in dir until it exits.
+func shellCmd(dir string) tea.Cmd {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	cmd := exec.Command(shell)
+	cmd.Dir = dir
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return shellExitedMsg{dir: dir, err: err}
+	})
+}
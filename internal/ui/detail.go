@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+// splitMinWidth is the terminal width from which the details of the selected
+// entry are shown next to the list rather than only with the info key.
+const splitMinWidth = 160
+
+// detailState is the lazily loaded detail of a single pull request.
+type detailState struct {
+	loading bool
+	// pullRequest is kept while a newer version is loading.
+	pullRequest *model.GithubPullRequest
+	err         error
+
+	// body caches the Markdown body rendered at bodyWidth columns.
+	body      string
+	bodyWidth int
+}
+
+// renderBody renders the Markdown body, reusing the last rendering at the same width.
+func (d *detailState) renderBody(width int) string {
+	if d.bodyWidth != width || d.body == "" {
+		d.body = renderMarkdown(d.pullRequest.Body, width)
+		d.bodyWidth = width
+	}
+	return d.body
+}
+
+// detailLoadedMsg carries the details of the pull request at url.
+type detailLoadedMsg struct {
+	url string
+	// state is where the details go; a result for a replaced state is dropped.
+	state       *detailState
+	pullRequest *model.GithubPullRequest
+	err         error
+}
+
+// isSplit reports whether the details are shown next to the list.
+func (m *ListModel) isSplit() bool {
+	return m.width >= splitMinWidth
+}
+
+// detailCmd loads the details of the highlighted entry while they are shown,
+// unless they are cached and not older than the entry or already loading.
+func (m *ListModel) detailCmd() tea.Cmd {
+	if !(m.showInfo || m.isSplit()) {
+		return nil
+	}
+	entry, ok := m.SelectedEntry()
+	if !ok {
+		return nil
+	}
+
+	if previous := m.details[entry.URL]; previous != nil {
+		if previous.loading || previous.err != nil {
+			return nil
+		}
+		if !previous.pullRequest.UpdatedAt.Before(entry.UpdatedAt) {
+			return nil
+		}
+	}
+	return m.loadDetailCmd(entry)
+}
+
+// reloadEntryCmd reloads a pull request after it was changed, updating it
+// wherever it is listed.
+func (m *ListModel) reloadEntryCmd(entry Entry) tea.Cmd {
+	if state := m.details[entry.URL]; state != nil && state.loading {
+		// The result of the load in flight may predate the change.
+		delete(m.details, entry.URL)
+	}
+	return m.loadDetailCmd(entry)
+}
+
+// loadDetailCmd fetches the details of entry, showing what was loaded before meanwhile.
+func (m *ListModel) loadDetailCmd(entry Entry) tea.Cmd {
+	fetcher, ok := m.fetcher.(pullrequest.DetailFetcher)
+	if !ok {
+		return nil
+	}
+
+	state := &detailState{loading: true}
+	if previous := m.details[entry.URL]; previous != nil {
+		state.pullRequest = previous.pullRequest
+	}
+	m.details[entry.URL] = state
+
+	ctx := m.ctx
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+		defer cancel()
+		pullRequest, err := fetcher.PullRequest(ctx, entry.RepositoryNameWithOwner, entry.PrNumber)
+		return detailLoadedMsg{url: entry.URL, state: state, pullRequest: pullRequest, err: err}
+	}
+}
+
+// setDetail stores loaded details and updates the listed pull request with
+// them. A failure keeps what was loaded before and is only retried by a refresh.
+func (m *ListModel) setDetail(msg detailLoadedMsg) {
+	state, ok := m.details[msg.url]
+	if !ok || state != msg.state {
+		return
+	}
+	if errors.Is(msg.err, context.Canceled) {
+		delete(m.details, msg.url)
+		return
+	}
+	state.loading = false
+	state.err = msg.err
+	if msg.err == nil {
+		state.pullRequest = msg.pullRequest
+		state.body = ""
+		m.updatePullRequest(msg.pullRequest)
+	}
+}
+
+// forgetFailedDetails lets details that failed to load be fetched again.
+func (m *ListModel) forgetFailedDetails() {
+	for url, state := range m.details {
+		if state.err != nil {
+			delete(m.details, url)
+		}
+	}
+}
+
+// detailView renders the details of entry in a bordered panel of width by height cells,
+// cutting off what does not fit.
+func (m *ListModel) detailView(entry Entry, width, height int) string {
+	h, v := infoStyle.GetFrameSize()
+	content := renderInfo(entry, m.details[entry.URL], width-h)
+	lines := strings.Split(lipgloss.NewStyle().Width(width-h).Render(content), "\n")
+	if len(lines) > height-v {
+		lines = append(lines[:max(height-v-1, 0)], infoDimStyle.Render("… press i to read more"))
+	}
+	return infoStyle.Width(width - infoStyle.GetHorizontalBorderSize()).Render(strings.Join(lines, "\n"))
+}
+
+// infoView renders the details of entry full-screen in the scrollable viewport.
+func (m *ListModel) infoView(entry Entry) string {
+	if m.infoURL != entry.URL {
+		m.infoURL = entry.URL
+		m.info.GotoTop()
+	}
+	m.info.SetContent(lipgloss.NewStyle().Width(m.info.Width).Render(renderInfo(entry, m.details[entry.URL], m.info.Width)))
+	return infoStyle.Render(m.info.View())
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	dialogStyle = lipgloss.NewStyle().
+			Padding(0, 1).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("205"))
+
+	// selectedItemStyle marks the item under the cursor in dialogs and the diff file list.
+	selectedItemStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+)
+
+// dialog is a modal that replaces the list and takes every key until it closes.
+type dialog interface {
+	// Update handles a message and returns the dialog to show next, nil once it is closed.
+	Update(msg tea.Msg) (dialog, tea.Cmd)
+	View() string
+}
+
+// editorFinishedMsg carries the text written in $EDITOR.
+type editorFinishedMsg struct {
+	text string
+	err  error
+}
+
+// editCmd suspends the TUI to edit text in $VISUAL or $EDITOR, falling back to vi.
+func editCmd(text string) tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file, err := os.CreateTemp("", "gh-rr-*.md")
+	if err != nil {
+		return func() tea.Msg { return editorFinishedMsg{text: text, err: err} }
+	}
+	_, err = file.WriteString(text)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return func() tea.Msg { return editorFinishedMsg{text: text, err: err} }
+	}
+
+	args := append(strings.Fields(editor), file.Name())
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		defer os.Remove(file.Name())
+		if err != nil {
+			return editorFinishedMsg{text: text, err: err}
+		}
+		edited, err := os.ReadFile(file.Name())
+		if err != nil {
+			return editorFinishedMsg{text: text, err: err}
+		}
+		return editorFinishedMsg{text: strings.TrimRight(string(edited), "\n")}
+	})
+}
@@ -0,0 +1,738 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/diff"
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+var (
+	diffFileHeaderStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("230")).
+				Background(lipgloss.Color("236"))
+
+	diffHunkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	diffGutterStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	diffMatchStyle   = lipgloss.NewStyle().Reverse(true)
+	diffThreadStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("75"))
+	diffPendingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	diffFileListStyle = lipgloss.NewStyle().
+				Padding(0, 1).
+				Border(lipgloss.NormalBorder(), false, true, false, false).
+				BorderForeground(lipgloss.Color("240"))
+)
+
+// diffFileListWidth is the widest the file list of the diff view gets.
+const diffFileListWidth = 40
+
+// diffCommentIndent lines comments up with the code, past the line numbers and marker.
+const diffCommentIndent = "           "
+
+// diffLoadedMsg carries the diff of the pull request at url.
+type diffLoadedMsg struct {
+	url  string
+	diff string
+	err  error
+}
+
+// diffThreadsLoadedMsg carries the review threads of the pull request at url.
+type diffThreadsLoadedMsg struct {
+	url     string
+	threads []*pullrequest.Thread
+	err     error
+}
+
+// diffRow is a line of the diff view: a file header, a line of the diff or
+// a comment on one.
+type diffRow struct {
+	rendered string
+	// text is the plain text of the row, for searching.
+	text string
+	file int
+	// line is the index of the row in the file's Lines, -1 for headers and comments.
+	line int
+}
+
+// diffAction is what a key pressed in the diff view asks the list to do.
+type diffAction int
+
+const (
+	diffNone diffAction = iota
+	diffClose
+	// diffComment writes a comment on the selected lines.
+	diffComment
+	// diffApprove, diffCommentReview and diffRequestChanges submit the
+	// pending comments with a verdict.
+	diffApprove
+	diffCommentReview
+	diffRequestChanges
+)
+
+// diffReviewEvents maps the review actions of the diff view to their verdicts.
+var diffReviewEvents = map[diffAction]pullrequest.ReviewEvent{
+	diffApprove:        pullrequest.ReviewApprove,
+	diffCommentReview:  pullrequest.ReviewComment,
+	diffRequestChanges: pullrequest.ReviewRequestChanges,
+}
+
+// diffModel shows the diff of a pull request with a file list and search,
+// and collects comments on its lines into a pending review.
+type diffModel struct {
+	entry   Entry
+	loading bool
+	err     error
+	files   []diff.File
+	// highlighted are the rendered lines of every file.
+	highlighted [][]string
+
+	rows []diffRow
+	// fileStarts is the index in rows of every file's header.
+	fileStarts []int
+	// file is the index of the current file, the one the cursor is in.
+	file int
+	// cursor is the row under the cursor; anchor is the other end of the
+	// selected range, or -1 when a single line is selected.
+	cursor int
+	anchor int
+
+	// threads are the review threads already on the pull request, and
+	// threadsErr why they could not be loaded.
+	threads    []*pullrequest.Thread
+	threadsErr error
+	// pending are the comments of the review being written.
+	pending []pullrequest.DraftComment
+
+	viewport  viewport.Model
+	showFiles bool
+	width     int
+	height    int
+
+	search    textinput.Model
+	searching bool
+	query     string
+	// matches are the indexes of the rows containing the search query.
+	matches []int
+	match   int
+}
+
+func newDiffModel(entry Entry, width, height int) *diffModel {
+	search := textinput.New()
+	search.Prompt = "/"
+	d := &diffModel{entry: entry, loading: true, anchor: -1, showFiles: true, search: search}
+	d.setSize(width, height)
+	return d
+}
+
+// loadDiffCmd fetches the diff of entry.
+func (m *ListModel) loadDiffCmd(fetcher pullrequest.DiffFetcher, entry Entry) tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+		defer cancel()
+		text, err := fetcher.Diff(ctx, entry.RepositoryNameWithOwner, entry.PrNumber)
+		return diffLoadedMsg{url: entry.URL, diff: text, err: err}
+	}
+}
+
+// loadDiffThreadsCmd fetches the review threads of entry to show them in the
+// diff, or does nothing when the fetcher cannot.
+func (m *ListModel) loadDiffThreadsCmd(entry Entry) tea.Cmd {
+	fetcher, ok := m.fetcher.(pullrequest.TimelineFetcher)
+	if !ok {
+		return nil
+	}
+	ctx := m.ctx
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+		defer cancel()
+		timeline, err := fetcher.Timeline(ctx, entry.RepositoryNameWithOwner, entry.PrNumber)
+		if err != nil {
+			return diffThreadsLoadedMsg{url: entry.URL, err: err}
+		}
+		var threads []*pullrequest.Thread
+		for _, item := range timeline.Items {
+			if item.Thread != nil {
+				threads = append(threads, item.Thread)
+			}
+		}
+		return diffThreadsLoadedMsg{url: entry.URL, threads: threads}
+	}
+}
+
+// setSize fits the diff view into width by height cells: a header line, the
+// file list and diff, and a help line.
+func (d *diffModel) setSize(width, height int) {
+	d.width, d.height = width, height
+	d.viewport.Width = width
+	if d.showFiles && len(d.files) > 1 {
+		d.viewport.Width -= d.fileListWidth() + diffFileListStyle.GetHorizontalFrameSize()
+	}
+	d.viewport.Height = max(height-2, 1)
+}
+
+func (d *diffModel) fileListWidth() int {
+	return min(diffFileListWidth, d.width/3)
+}
+
+// setDiff renders the loaded diff.
+func (d *diffModel) setDiff(text string, err error) {
+	d.loading = false
+	d.err = err
+	if err != nil {
+		return
+	}
+
+	d.files = diff.Parse(text)
+	d.highlighted = make([][]string, len(d.files))
+	for i, file := range d.files {
+		lexer := lexerFor(file.Path())
+		for _, line := range file.Lines {
+			d.highlighted[i] = append(d.highlighted[i], renderDiffLine(line, lexer))
+		}
+	}
+	d.setSize(d.width, d.height)
+	d.layout()
+}
+
+// setThreads shows the review threads of the pull request next to the lines they are on.
+func (d *diffModel) setThreads(threads []*pullrequest.Thread, err error) {
+	d.threads, d.threadsErr = threads, err
+	d.layout()
+}
+
+// addPending adds comment to the pending review.
+func (d *diffModel) addPending(comment pullrequest.DraftComment) {
+	d.pending = append(d.pending, comment)
+	d.anchor = -1
+	d.layout()
+}
+
+// removePending drops the pending comments on the line under the cursor and
+// reports how many there were.
+func (d *diffModel) removePending() int {
+	if d.cursor >= len(d.rows) {
+		return 0
+	}
+	row := d.rows[d.cursor]
+	if row.line < 0 {
+		return 0
+	}
+	file := d.files[row.file]
+	kept := d.pending[:0]
+	for _, comment := range d.pending {
+		if comment.Path != file.Path() || lineIndex(file, comment.Line, comment.Side) != row.line {
+			kept = append(kept, comment)
+		}
+	}
+	removed := len(d.pending) - len(kept)
+	d.pending = kept
+	d.layout()
+	return removed
+}
+
+// clearPending forgets the pending comments once their review was submitted.
+func (d *diffModel) clearPending() {
+	d.pending = nil
+	d.layout()
+}
+
+// layout builds the rows of the diff: every file with the threads and
+// pending comments under the lines they are on. Threads that no longer map
+// onto the diff are listed under the file header. The cursor stays on the
+// same line of the diff.
+func (d *diffModel) layout() {
+	at := diffRow{line: -1}
+	if d.cursor < len(d.rows) {
+		at = d.rows[d.cursor]
+	}
+
+	d.rows, d.fileStarts = nil, nil
+	for i, file := range d.files {
+		d.fileStarts = append(d.fileStarts, len(d.rows))
+		header := fmt.Sprintf("%s  +%d −%d", file.Path(), file.Additions, file.Deletions)
+		d.rows = append(d.rows, diffRow{rendered: diffFileHeaderStyle.Render(" " + header + " "), text: header, file: i, line: -1})
+		if file.Binary {
+			d.rows = append(d.rows, diffRow{rendered: diffGutterStyle.Render("  binary file"), file: i, line: -1})
+		}
+
+		threads := map[int][]*pullrequest.Thread{}
+		for _, thread := range d.threads {
+			if thread.Path != file.Path() {
+				continue
+			}
+			j := lineIndex(file, thread.Line, thread.Side)
+			if j < 0 {
+				d.rows = append(d.rows, threadRows(thread, i, true)...)
+				continue
+			}
+			threads[j] = append(threads[j], thread)
+		}
+		pending := map[int][]pullrequest.DraftComment{}
+		for _, comment := range d.pending {
+			if comment.Path == file.Path() {
+				j := lineIndex(file, comment.Line, comment.Side)
+				pending[j] = append(pending[j], comment)
+			}
+		}
+
+		for j, line := range file.Lines {
+			d.rows = append(d.rows, diffRow{rendered: d.highlighted[i][j], text: line.Text, file: i, line: j})
+			for _, thread := range threads[j] {
+				d.rows = append(d.rows, threadRows(thread, i, false)...)
+			}
+			for _, comment := range pending[j] {
+				text := "✎ pending: " + firstLine(comment.Body)
+				d.rows = append(d.rows, diffRow{rendered: diffPendingStyle.Render(diffCommentIndent + text), text: text, file: i, line: -1})
+			}
+		}
+		d.rows = append(d.rows, diffRow{file: i, line: -1})
+	}
+
+	d.cursor = min(d.cursor, max(len(d.rows)-1, 0))
+	if at.line >= 0 {
+		for i, row := range d.rows {
+			if row.file == at.file && row.line == at.line {
+				d.cursor = i
+				break
+			}
+		}
+	}
+	if d.anchor >= len(d.rows) {
+		d.anchor = -1
+	}
+	d.file = d.fileAt(d.cursor)
+	d.matches = d.matchRows(d.query)
+	d.match = min(d.match, max(len(d.matches)-1, 0))
+	d.refresh()
+}
+
+// threadRows renders a review thread: its first comment with whether it is
+// resolved or outdated, and the first line of every reply. Threads that are
+// not on a line of the diff also tell which line they were on.
+func threadRows(thread *pullrequest.Thread, file int, unanchored bool) []diffRow {
+	style := diffThreadStyle
+	var marks []string
+	if unanchored {
+		marks = append(marks, fmt.Sprintf("line %d", max(thread.Line, thread.OriginalLine)))
+	}
+	if thread.Resolved {
+		marks = append(marks, "✓ resolved")
+		style = infoDimStyle
+	}
+	if thread.Outdated {
+		marks = append(marks, "outdated")
+		style = infoDimStyle
+	}
+
+	var rows []diffRow
+	for i, comment := range thread.Comments {
+		text := "↳ " + comment.Author + ": " + firstLine(comment.Body)
+		if i == 0 {
+			text = "» " + comment.Author + ": " + firstLine(comment.Body)
+			if len(marks) > 0 {
+				text += " · " + strings.Join(marks, " · ")
+			}
+		}
+		rows = append(rows, diffRow{rendered: style.Render(diffCommentIndent + text), text: text, file: file, line: -1})
+	}
+	return rows
+}
+
+// lineIndex is the index in file.Lines of line number on side, or -1 when
+// the diff does not show it.
+func lineIndex(file diff.File, number int, side string) int {
+	if number == 0 {
+		return -1
+	}
+	for j, line := range file.Lines {
+		switch {
+		case line.Kind == diff.HunkHeader:
+		case side == pullrequest.SideLeft:
+			if line.Kind != diff.Added && line.OldNumber == number {
+				return j
+			}
+		case line.Kind != diff.Removed && line.NewNumber == number:
+			return j
+		}
+	}
+	return -1
+}
+
+// selection is the first and last row of the selected lines.
+func (d *diffModel) selection() (int, int) {
+	if d.anchor < 0 {
+		return d.cursor, d.cursor
+	}
+	return min(d.anchor, d.cursor), max(d.anchor, d.cursor)
+}
+
+// draftComment anchors a comment to the selected lines. It fails unless the
+// selection starts and ends on code lines of a single file.
+func (d *diffModel) draftComment() (pullrequest.DraftComment, bool) {
+	first, last := d.selection()
+	if len(d.rows) == 0 {
+		return pullrequest.DraftComment{}, false
+	}
+	start, end := d.rows[first], d.rows[last]
+	if start.line < 0 || end.line < 0 || start.file != end.file {
+		return pullrequest.DraftComment{}, false
+	}
+	file := d.files[end.file]
+	startLine, endLine := file.Lines[start.line], file.Lines[end.line]
+	if startLine.Kind == diff.HunkHeader || endLine.Kind == diff.HunkHeader {
+		return pullrequest.DraftComment{}, false
+	}
+
+	comment := pullrequest.DraftComment{Path: file.Path()}
+	comment.Line, comment.Side = lineSide(endLine)
+	if first != last {
+		comment.StartLine, comment.StartSide = lineSide(startLine)
+	}
+	return comment, true
+}
+
+// lineSide is the line number and diff side a comment on line is anchored to.
+func lineSide(line diff.Line) (int, string) {
+	if line.Kind == diff.Removed {
+		return line.OldNumber, pullrequest.SideLeft
+	}
+	return line.NewNumber, pullrequest.SideRight
+}
+
+// refresh puts the rows into the viewport, marking the selected lines and
+// highlighting search matches.
+func (d *diffModel) refresh() {
+	matched := map[int]bool{}
+	for _, i := range d.matches {
+		matched[i] = true
+	}
+	first, last := d.selection()
+	lines := make([]string, len(d.rows))
+	for i, row := range d.rows {
+		text := row.rendered
+		if matched[i] {
+			text = diffMatchStyle.Render(row.text)
+		}
+		marker := " "
+		if i >= first && i <= last {
+			marker = selectedItemStyle.Render("▌")
+		}
+		lines[i] = marker + text
+	}
+	d.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// fileAt is the index of the file row belongs to.
+func (d *diffModel) fileAt(row int) int {
+	current := 0
+	for i, start := range d.fileStarts {
+		if start <= row {
+			current = i
+		}
+	}
+	return current
+}
+
+// moveCursor puts the cursor on row, scrolling to keep it visible.
+func (d *diffModel) moveCursor(row int) {
+	if len(d.rows) == 0 {
+		return
+	}
+	d.cursor = max(0, min(row, len(d.rows)-1))
+	d.file = d.fileAt(d.cursor)
+	if d.cursor < d.viewport.YOffset {
+		d.viewport.SetYOffset(d.cursor)
+	} else if d.cursor >= d.viewport.YOffset+d.viewport.Height {
+		d.viewport.SetYOffset(d.cursor - d.viewport.Height + 1)
+	}
+	d.refresh()
+}
+
+func (d *diffModel) gotoFile(i int) {
+	if len(d.fileStarts) == 0 {
+		return
+	}
+	i = (i + len(d.fileStarts)) % len(d.fileStarts)
+	d.viewport.SetYOffset(d.fileStarts[i])
+	d.moveCursor(d.fileStarts[i])
+}
+
+// matchRows are the rows containing query, ignoring case.
+func (d *diffModel) matchRows(query string) []int {
+	query = strings.ToLower(query)
+	if query == "" {
+		return nil
+	}
+	var matches []int
+	for i, row := range d.rows {
+		if strings.Contains(strings.ToLower(row.text), query) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// find collects the rows containing query and jumps to the first one below
+// the top of the viewport.
+func (d *diffModel) find(query string) {
+	d.query = query
+	d.matches, d.match = d.matchRows(query), 0
+	d.refresh()
+	for i, row := range d.matches {
+		if row >= d.viewport.YOffset {
+			d.match = i
+			break
+		}
+	}
+	d.gotoMatch(d.match)
+}
+
+func (d *diffModel) gotoMatch(i int) {
+	if len(d.matches) == 0 {
+		return
+	}
+	d.match = (i + len(d.matches)) % len(d.matches)
+	d.viewport.SetYOffset(d.matches[d.match] - d.viewport.Height/2)
+	d.moveCursor(d.matches[d.match])
+}
+
+// Update handles a key while the diff is shown and reports what the list should do.
+func (d *diffModel) Update(msg tea.KeyMsg) (diffAction, tea.Cmd) {
+	if d.searching {
+		switch msg.String() {
+		case "enter":
+			d.searching = false
+			d.search.Blur()
+			d.find(d.search.Value())
+			return diffNone, nil
+		case "esc":
+			d.searching = false
+			d.search.Blur()
+			return diffNone, nil
+		}
+		var cmd tea.Cmd
+		d.search, cmd = d.search.Update(msg)
+		return diffNone, cmd
+	}
+
+	switch msg.String() {
+	case "esc":
+		if d.anchor >= 0 {
+			d.anchor = -1
+			d.refresh()
+			return diffNone, nil
+		}
+		return diffClose, nil
+	case "q":
+		return diffClose, nil
+	case "up", "k":
+		d.moveCursor(d.cursor - 1)
+	case "down", "j":
+		d.moveCursor(d.cursor + 1)
+	case "V":
+		if d.anchor >= 0 {
+			d.anchor = -1
+		} else {
+			d.anchor = d.cursor
+		}
+		d.refresh()
+	case "c":
+		if d.loading || d.err != nil {
+			return diffNone, nil
+		}
+		return diffComment, nil
+	case "X":
+		d.removePending()
+	case "a":
+		return diffApprove, nil
+	case "m":
+		return diffCommentReview, nil
+	case "x":
+		return diffRequestChanges, nil
+	case "]":
+		d.gotoFile(d.file + 1)
+	case "[":
+		d.gotoFile(d.file - 1)
+	case "tab":
+		d.showFiles = !d.showFiles
+		d.setSize(d.width, d.height)
+	case "/":
+		d.searching = true
+		d.search.SetValue("")
+		return diffNone, d.search.Focus()
+	case "n":
+		d.gotoMatch(d.match + 1)
+	case "N":
+		d.gotoMatch(d.match - 1)
+	case "home", "g":
+		d.viewport.GotoTop()
+		d.moveCursor(0)
+	case "end", "G":
+		d.viewport.GotoBottom()
+		d.moveCursor(len(d.rows) - 1)
+	default:
+		offset := d.viewport.YOffset
+		var cmd tea.Cmd
+		d.viewport, cmd = d.viewport.Update(msg)
+		if d.viewport.YOffset != offset {
+			// Keep the cursor on screen while scrolling.
+			d.moveCursor(max(d.viewport.YOffset, min(d.cursor, d.viewport.YOffset+d.viewport.Height-1)))
+		}
+		return diffNone, cmd
+	}
+	return diffNone, nil
+}
+
+func (d *diffModel) View(spinner string) string {
+	title := infoHeadingStyle.Render(fmt.Sprintf("%s#%d — %s", d.entry.RepositoryNameWithOwner, d.entry.PrNumber, d.entry.Title))
+	switch {
+	case d.loading:
+		return lipgloss.JoinVertical(lipgloss.Left, title, infoDimStyle.Render(spinner+" Loading diff…"))
+	case d.err != nil:
+		return lipgloss.JoinVertical(lipgloss.Left, title,
+			checkStyles[model.CheckFailing].Render("⚠ Failed to load diff: "+errorDetail(d.err)),
+			infoDimStyle.Render("esc to go back"))
+	case len(d.files) == 0:
+		return lipgloss.JoinVertical(lipgloss.Left, title, infoDimStyle.Render("No changes."))
+	}
+
+	status := fmt.Sprintf("file %d/%d · %3.f%%", d.file+1, len(d.files), d.viewport.ScrollPercent()*100)
+	if len(d.matches) > 0 {
+		status += fmt.Sprintf(" · match %d/%d", d.match+1, len(d.matches))
+	} else if d.query != "" {
+		status += " · no matches"
+	}
+	if len(d.pending) > 0 {
+		status += fmt.Sprintf(" · %d pending", len(d.pending))
+	}
+	if d.threadsErr != nil {
+		status += " · review comments unavailable: " + errorDetail(d.threadsErr)
+	}
+	header := title + infoDimStyle.Render(" · "+status)
+
+	body := d.viewport.View()
+	if d.showFiles && len(d.files) > 1 {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, d.fileListView(), body)
+	}
+
+	help := "↑/↓ move · [/] prev/next file · tab toggle files · / search · n/N next/prev match · V select range · c comment · esc back"
+	switch {
+	case d.anchor >= 0:
+		help = "↑/↓ extend selection · c comment on selection · esc clear selection"
+	case len(d.pending) > 0:
+		help = "↑/↓ move · V select range · c comment · X drop pending · a/m/x submit review · esc back"
+	}
+	footer := infoDimStyle.Render(help)
+	if d.searching {
+		footer = d.search.View()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, footer)
+}
+
+// fileListView lists every file with its +/- counts, marking the current one
+// and scrolling to keep it visible.
+func (d *diffModel) fileListView() string {
+	width := d.fileListWidth()
+	height := d.viewport.Height
+	current := d.file
+	first := max(0, min(current-height/2, len(d.files)-height))
+
+	var lines []string
+	for i := first; i < len(d.files) && i < first+height; i++ {
+		file := d.files[i]
+		counts := diffAddedStyle.Render(fmt.Sprintf("+%d", file.Additions)) + " " +
+			diffRemovedStyle.Render(fmt.Sprintf("−%d", file.Deletions))
+		name := truncateLeft(file.Path(), width-lipgloss.Width(counts)-3)
+		if i == current {
+			name = selectedItemStyle.Render("▸ " + name)
+		} else {
+			name = "  " + name
+		}
+		gap := max(width-lipgloss.Width(name)-lipgloss.Width(counts), 1)
+		lines = append(lines, name+strings.Repeat(" ", gap)+counts)
+	}
+	return diffFileListStyle.Height(height).Render(strings.Join(lines, "\n"))
+}
+
+// truncateLeft shortens path to width cells, keeping its end.
+func truncateLeft(path string, width int) string {
+	runes := []rune(path)
+	if len(runes) <= width || width < 2 {
+		return path
+	}
+	return "…" + string(runes[len(runes)-width+1:])
+}
+
+// renderDiffLine renders a line with its old and new line numbers,
+// highlighting the code with lexer when there is one.
+func renderDiffLine(line diff.Line, lexer chroma.Lexer) string {
+	if line.Kind == diff.HunkHeader {
+		return diffHunkStyle.Render(line.Text)
+	}
+
+	gutter := diffGutterStyle.Render(fmt.Sprintf("%4s %4s ", lineNumber(line.OldNumber), lineNumber(line.NewNumber)))
+	marker := " "
+	switch line.Kind {
+	case diff.Added:
+		marker = diffAddedStyle.Render("+")
+	case diff.Removed:
+		marker = diffRemovedStyle.Render("-")
+	}
+	return gutter + marker + highlight(line.Text, lexer)
+}
+
+func lineNumber(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
+
+// lexerFor returns the syntax lexer for a file, or nil when there is none.
+func lexerFor(path string) chroma.Lexer {
+	lexer := lexers.Match(path)
+	if lexer == nil {
+		return nil
+	}
+	return chroma.Coalesce(lexer)
+}
+
+// highlight colours a line of code with lexer, returning it as is without one.
+func highlight(code string, lexer chroma.Lexer) string {
+	code = strings.ReplaceAll(code, "\t", "    ")
+	if lexer == nil {
+		return code
+	}
+	iterator, err := lexer.Tokenise(nil, code)
+	if err != nil {
+		return code
+	}
+	style := styles.Get("monokai")
+	if markdownTheme() == "light" {
+		style = styles.Get("github")
+	}
+	var sb strings.Builder
+	if err := formatters.TTY256.Format(&sb, style, iterator); err != nil {
+		return code
+	}
+	// Lexers end the line with a newline, sometimes inside a styled token.
+	return strings.ReplaceAll(sb.String(), "\n", "")
+}
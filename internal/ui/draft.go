@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+// draftChangedMsg reports the outcome of marking a pull request ready for
+// review, or converting it to a draft when draft is set.
+type draftChangedMsg struct {
+	entry Entry
+	draft bool
+	err   error
+}
+
+// draftDialog confirms changing the draft state of a pull request.
+type draftDialog struct {
+	entry Entry
+	// draft is the state to change to.
+	draft   bool
+	running bool
+	err     error
+	run     func() tea.Cmd
+}
+
+// isDraft reports whether entry is a draft. Entries listed by a search for
+// drafts are, even when the fetcher does not report the draft state.
+func (m *ListModel) isDraft(entry Entry) bool {
+	return entry.IsDraft || strings.Contains(m.Categories[m.CategoryIndex].Search().String(), string(pullrequest.DraftTrue))
+}
+
+// openDraft asks to mark the selected draft ready for review, or to convert
+// the selected pull request to a draft.
+func (m *ListModel) openDraft() tea.Cmd {
+	toggler, ok := m.fetcher.(pullrequest.DraftToggler)
+	entry, selected := m.SelectedEntry()
+	if !ok || !selected {
+		return nil
+	}
+
+	ctx, draft := m.ctx, !m.isDraft(entry)
+	m.dialog = &draftDialog{
+		entry: entry,
+		draft: draft,
+		run: func() tea.Cmd {
+			return func() tea.Msg {
+				ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+				defer cancel()
+				err := toggler.SetDraft(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, draft)
+				return draftChangedMsg{entry: entry, draft: draft, err: err}
+			}
+		},
+	}
+	return nil
+}
+
+func (d *draftDialog) verb() string {
+	if d.draft {
+		return "Convert to draft"
+	}
+	return "Mark ready for review"
+}
+
+func (d *draftDialog) Update(msg tea.Msg) (dialog, tea.Cmd) {
+	switch msg := msg.(type) {
+	case draftChangedMsg:
+		d.running = false
+		d.err = msg.err
+	case tea.KeyMsg:
+		if d.running {
+			return d, nil
+		}
+		switch msg.String() {
+		case "y", "enter":
+			d.err = nil
+			d.running = true
+			return d, d.run()
+		case "n", "esc", "q":
+			return nil, nil
+		}
+	}
+	return d, nil
+}
+
+func (d *draftDialog) View() string {
+	lines := []string{
+		infoHeadingStyle.Render(fmt.Sprintf("%s: %s#%d — %s", d.verb(), d.entry.RepositoryNameWithOwner, d.entry.PrNumber, d.entry.Title)),
+		"",
+	}
+	if d.draft {
+		lines = append(lines, "Reviewers will not be notified of changes while it is a draft.")
+	} else {
+		lines = append(lines, "Requested reviewers will be notified.")
+	}
+	lines = append(lines, "")
+	if d.err != nil {
+		lines = append(lines, checkStyles[model.CheckFailing].Render("⚠ "+errorDetail(d.err)))
+	}
+	if d.running {
+		lines = append(lines, infoDimStyle.Render(d.verb()+"…"))
+	} else {
+		lines = append(lines, infoDimStyle.Render("y confirm · n cancel"))
+	}
+	return dialogStyle.Render(strings.Join(lines, "\n"))
+}
+
+// draftChanged updates the list after the draft state changed: the pull
+// request leaves the tabs for the other state and shows up in the right ones
+// with the next refresh.
+func (m *ListModel) draftChanged(msg draftChangedMsg) tea.Cmd {
+	done, leaving := "Marked ready for review", pullrequest.DraftTrue
+	if msg.draft {
+		done, leaving = "Converted to draft", pullrequest.DraftFalse
+	}
+	m.notice = fmt.Sprintf("✓ %s %s#%d", done, msg.entry.RepositoryNameWithOwner, msg.entry.PrNumber)
+	for i, c := range m.Categories {
+		if strings.Contains(c.Search().String(), string(leaving)) {
+			m.removePullRequest(i, msg.entry.URL)
+		}
+	}
+	return m.reloadEntryCmd(msg.entry)
+}
@@ -19,6 +19,30 @@ type Entry struct {
 	Author                  string
 	PrNumber                int
 	CommentsCount           int
+	CheckState              model.CheckState
+	FailingChecks           []string
+	ReviewDecision          string
+	Reviewers               []model.Reviewer
+	UpdatedAt               time.Time
+	IsDraft                 bool
+	Labels                  []model.Label
+	Assignees               []string
+	// Partial marks an entry listed without its CI and review state.
+	Partial bool
+}
+
+// checkIcons marks the aggregate CI state of an entry.
+var checkIcons = map[model.CheckState]string{
+	model.CheckPassing: "✓",
+	model.CheckFailing: "✗",
+	model.CheckPending: "●",
+}
+
+// reviewDecisions describes the review decision of an entry.
+var reviewDecisions = map[string]string{
+	"APPROVED":          "✓ approved",
+	"CHANGES_REQUESTED": "✗ changes requested",
+	"REVIEW_REQUIRED":   "● review required",
 }
 
 // categoryItem wraps a category name for the category List.
@@ -26,10 +50,18 @@ type Entry struct {
 type itemEntry struct{ entry Entry }
 
 func (i itemEntry) Title() string {
-	return fmt.Sprintf("%s — %s - %d", i.entry.RepositoryNameWithOwner, i.entry.Title, i.entry.PrNumber)
+	title := fmt.Sprintf("%s — %s - %d", i.entry.RepositoryNameWithOwner, i.entry.Title, i.entry.PrNumber)
+	if icon, ok := checkIcons[i.entry.CheckState]; ok {
+		return icon + " " + title
+	}
+	return title
 }
 func (i itemEntry) Description() string {
-	return fmt.Sprintf("Age: %s, LastUpdatedSince: %s, Author: %s, CommentCount: %d", i.entry.AgeStr, i.entry.LastUpdatedSinceStr, i.entry.Author, i.entry.CommentsCount)
+	description := fmt.Sprintf("Age: %s, LastUpdatedSince: %s, Author: %s, CommentCount: %d", i.entry.AgeStr, i.entry.LastUpdatedSinceStr, i.entry.Author, i.entry.CommentsCount)
+	if decision, ok := reviewDecisions[i.entry.ReviewDecision]; ok {
+		description += ", Review: " + decision
+	}
+	return description
 }
 func (i itemEntry) FilterValue() string { return i.entry.RepositoryNameWithOwner + " " + i.entry.Title }
 
@@ -56,6 +88,15 @@ func BuildEntries(pullRequests []*model.GithubPullRequest, now time.Time) []Entr
 			Author:                  pullRequest.AuthorSlug,
 			PrNumber:                pullRequest.PrNumber,
 			CommentsCount:           pullRequest.CommentsCount,
+			CheckState:              pullRequest.CheckState(),
+			FailingChecks:           pullRequest.FailingChecks,
+			ReviewDecision:          pullRequest.ReviewDecision,
+			Reviewers:               pullRequest.Reviewers(),
+			UpdatedAt:               pullRequest.UpdatedAt,
+			IsDraft:                 pullRequest.IsDraft,
+			Labels:                  pullRequest.Labels,
+			Assignees:               pullRequest.Assignees,
+			Partial:                 pullRequest.Partial,
 		})
 	}
 	return entries
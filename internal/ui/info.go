@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cli/go-gh/v2/pkg/markdown"
+
+	"github.com/jinwoo1225/gh-rr/internal/model"
+)
+
+var (
+	infoStyle = lipgloss.NewStyle().
+			Padding(0, 1).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240"))
+
+	infoHeadingStyle = lipgloss.NewStyle().Bold(true)
+
+	infoDimStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	checkStyles = map[model.CheckState]lipgloss.Style{
+		model.CheckPassing: lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
+		model.CheckFailing: lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+		model.CheckPending: lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		model.CheckNone:    lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		model.CheckUnknown: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+	}
+
+	// reviewStates describes and colours the latest review of a reviewer.
+	reviewStates = map[string]struct {
+		label string
+		style lipgloss.Style
+	}{
+		"APPROVED":          {"✓ approved", checkStyles[model.CheckPassing]},
+		"CHANGES_REQUESTED": {"✗ changes requested", checkStyles[model.CheckFailing]},
+		"COMMENTED":         {"💬 commented", lipgloss.NewStyle()},
+		"DISMISSED":         {"dismissed", checkStyles[model.CheckNone]},
+		"PENDING":           {"pending", checkStyles[model.CheckNone]},
+	}
+)
+
+// renderInfo renders the details of an entry within width columns. The
+// branches, diff size, labels and body are shown once detail is loaded.
+func renderInfo(entry Entry, detail *detailState, width int) string {
+	if detail != nil && detail.pullRequest != nil {
+		entry = BuildEntries([]*model.GithubPullRequest{detail.pullRequest}, time.Now())[0]
+	}
+
+	lines := []string{
+		infoHeadingStyle.Render(entry.RepositoryNameWithOwner + " — " + entry.Title),
+		infoDimStyle.Render(fmt.Sprintf("#%d by %s · opened %s ago · updated %s ago", entry.PrNumber, entry.Author, entry.AgeStr, entry.LastUpdatedSinceStr)),
+	}
+	if detail != nil && detail.pullRequest != nil {
+		pr := detail.pullRequest
+		lines = append(lines,
+			"",
+			infoHeadingStyle.Render("Branch: ")+pr.HeadRefName+" → "+pr.BaseRefName,
+			infoHeadingStyle.Render("Changes: ")+
+				checkStyles[model.CheckPassing].Render(fmt.Sprintf("+%d", pr.Additions))+" "+
+				checkStyles[model.CheckFailing].Render(fmt.Sprintf("−%d", pr.Deletions))+
+				fmt.Sprintf(" · %d files changed", pr.ChangedFiles),
+		)
+		if len(pr.Labels) > 0 {
+			lines = append(lines, infoHeadingStyle.Render("Labels: ")+renderLabels(pr.Labels))
+		}
+		if len(pr.Assignees) > 0 {
+			lines = append(lines, infoHeadingStyle.Render("Assignees: ")+strings.Join(pr.Assignees, ", "))
+		}
+	}
+	lines = append(lines, "")
+	lines = append(lines, renderChecks(entry)...)
+	lines = append(lines, "")
+	lines = append(lines, renderReviewers(entry)...)
+
+	switch {
+	case detail == nil:
+	case detail.pullRequest != nil:
+		lines = append(lines, "", detail.renderBody(width))
+	case detail.err != nil:
+		lines = append(lines, "", checkStyles[model.CheckFailing].Render("⚠ Failed to load details: "+errorDetail(detail.err)),
+			infoDimStyle.Render("press r to refresh"))
+	case detail.loading:
+		lines = append(lines, "", infoDimStyle.Render("Loading details…"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderLabels renders labels as badges in their GitHub colours.
+func renderLabels(labels []model.Label) string {
+	badges := make([]string, 0, len(labels))
+	for _, label := range labels {
+		badges = append(badges, labelStyle(label.Color).Render(label.Name))
+	}
+	return strings.Join(badges, " ")
+}
+
+// labelStyle returns a badge style on color, a hex code without '#', with
+// black or white text depending on how light it is.
+func labelStyle(color string) lipgloss.Style {
+	style := lipgloss.NewStyle().Padding(0, 1)
+	rgb, err := strconv.ParseUint(color, 16, 32)
+	if err != nil || len(color) != 6 {
+		return style.Foreground(lipgloss.Color("240"))
+	}
+	r, g, b := rgb>>16&0xff, rgb>>8&0xff, rgb&0xff
+	foreground := "#ffffff"
+	if r*299+g*587+b*114 > 150_000 {
+		foreground = "#000000"
+	}
+	return style.Background(lipgloss.Color("#" + color)).Foreground(lipgloss.Color(foreground))
+}
+
+// markdownTheme picks the glamour theme matching the terminal background.
+var markdownTheme = sync.OnceValue(func() string {
+	if lipgloss.HasDarkBackground() {
+		return styles.DarkStyle
+	}
+	return styles.LightStyle
+})
+
+// renderMarkdown renders a pull request body for the terminal, falling back
+// to the raw text if it cannot be rendered.
+func renderMarkdown(body string, width int) string {
+	if strings.TrimSpace(body) == "" {
+		return infoDimStyle.Italic(true).Render("No description provided.")
+	}
+	rendered, err := markdown.Render(body,
+		markdown.WithTheme(markdownTheme()),
+		markdown.WithWrap(width),
+		markdown.WithoutIndentation(),
+	)
+	if err != nil {
+		return body
+	}
+	return strings.Trim(rendered, "\n")
+}
+
+// renderChecks lists the aggregate CI state and the name of every failing check.
+func renderChecks(entry Entry) []string {
+	state := entry.CheckState
+	if state == "" {
+		state = model.CheckNone
+	}
+	summary := string(state)
+	if icon, ok := checkIcons[state]; ok {
+		summary = icon + " " + summary
+	}
+
+	lines := []string{infoHeadingStyle.Render("Checks: ") + checkStyles[state].Render(summary)}
+	for _, name := range entry.FailingChecks {
+		lines = append(lines, checkStyles[model.CheckFailing].Render("  ✗ "+name))
+	}
+	return lines
+}
+
+// renderReviewers lists the review decision and every requested or completed
+// reviewer with their latest state.
+func renderReviewers(entry Entry) []string {
+	if entry.Partial {
+		return []string{infoHeadingStyle.Render("Review: ") + checkStyles[model.CheckUnknown].Render("unknown")}
+	}
+	decision, ok := reviewDecisions[entry.ReviewDecision]
+	if !ok {
+		decision = "not required"
+	}
+	lines := []string{infoHeadingStyle.Render("Review: ") + decision}
+	if len(entry.Reviewers) == 0 {
+		return append(lines, checkStyles[model.CheckNone].Render("  no reviewers"))
+	}
+
+	for _, reviewer := range entry.Reviewers {
+		var states []string
+		if state, ok := reviewStates[reviewer.State]; ok {
+			states = append(states, state.style.Render(state.label))
+		}
+		if reviewer.Requested {
+			states = append(states, checkStyles[model.CheckPending].Render("● review requested"))
+		}
+		lines = append(lines, "  "+reviewer.Name+"  "+strings.Join(states, ", "))
+	}
+	return lines
+}
@@ -6,12 +6,26 @@ import (
 
 // keymap for help
 type keyMap struct {
-	Left     key.Binding
-	Right    key.Binding
-	Enter    key.Binding
-	Refresh  key.Binding
-	Checkout key.Binding
-	Quit     key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	Enter     key.Binding
+	Info      key.Binding
+	Diff      key.Binding
+	Timeline  key.Binding
+	Approve   key.Binding
+	Comment   key.Binding
+	Reject    key.Binding
+	Merge     key.Binding
+	Reviewers key.Binding
+	Draft     key.Binding
+	Labels    key.Binding
+	Assignees key.Binding
+	AssignMe  key.Binding
+	Refresh   key.Binding
+	Retry     key.Binding
+	Checkout  key.Binding
+	Restore   key.Binding
+	Quit      key.Binding
 }
 
 var Keys = keyMap{
@@ -27,14 +41,70 @@ var Keys = keyMap{
 		key.WithKeys("enter"),
 		key.WithHelp("↵", "open PR in browser"),
 	),
+	Info: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "PR details"),
+	),
+	Diff: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "view diff"),
+	),
+	Timeline: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "conversation"),
+	),
+	Approve: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "approve"),
+	),
+	Comment: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "comment"),
+	),
+	Reject: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "request changes"),
+	),
+	Merge: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "merge my PR"),
+	),
+	Reviewers: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit reviewers"),
+	),
+	Draft: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "toggle draft"),
+	),
+	Labels: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "labels"),
+	),
+	Assignees: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "assignees"),
+	),
+	AssignMe: key.NewBinding(
+		key.WithKeys("@"),
+		key.WithHelp("@", "assign to me"),
+	),
 	Refresh: key.NewBinding(
 		key.WithKeys("r"),
 		key.WithHelp("r", "refresh PR list"),
 	),
+	Retry: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "retry failed tab"),
+	),
 	Checkout: key.NewBinding(
 		key.WithKeys("c"),
 		key.WithHelp("c", "checkout PR"),
 	),
+	Restore: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "restore stashed changes"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c"),
 		key.WithHelp("q", "quit"),
@@ -0,0 +1,325 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+// mergeMethods describes every merge method.
+var mergeMethods = map[pullrequest.MergeMethod]struct{ label, done, name string }{
+	pullrequest.MergeCommit: {"Create a merge commit", "Merged", "merge commit"},
+	pullrequest.MergeSquash: {"Squash and merge", "Squashed and merged", "squash"},
+	pullrequest.MergeRebase: {"Rebase and merge", "Rebased and merged", "rebase"},
+}
+
+// mergeStates explains the merge state status of a pull request.
+var mergeStates = map[string]string{
+	"BEHIND":    "the head branch is behind the base branch",
+	"BLOCKED":   "blocked by branch protection",
+	"CLEAN":     "ready to merge",
+	"DIRTY":     "merge conflicts",
+	"DRAFT":     "draft",
+	"HAS_HOOKS": "ready to merge, pre-receive hooks apply",
+	"UNKNOWN":   "mergeability is being computed",
+	"UNSTABLE":  "mergeable, but checks are not passing",
+}
+
+// mergeActionKind is what a merge dialog action does.
+type mergeActionKind int
+
+const (
+	mergeNow mergeActionKind = iota
+	enableAutoMerge
+	disableAutoMerge
+	updateBranch
+)
+
+// mergeAction is an action offered by the merge dialog.
+type mergeAction struct {
+	kind   mergeActionKind
+	method pullrequest.MergeMethod
+}
+
+func (a mergeAction) label() string {
+	switch a.kind {
+	case enableAutoMerge:
+		return "Enable auto-merge (" + mergeMethods[a.method].name + ")"
+	case disableAutoMerge:
+		return "Disable auto-merge"
+	case updateBranch:
+		return "Update branch from base"
+	default:
+		return mergeMethods[a.method].label
+	}
+}
+
+func (a mergeAction) done() string {
+	switch a.kind {
+	case enableAutoMerge:
+		return "Enabled auto-merge on"
+	case disableAutoMerge:
+		return "Disabled auto-merge on"
+	case updateBranch:
+		return "Updated the branch of"
+	default:
+		return mergeMethods[a.method].done
+	}
+}
+
+// mergeStatusMsg carries the merge status of a pull request.
+type mergeStatusMsg struct {
+	url    string
+	status *pullrequest.MergeStatus
+	err    error
+}
+
+// mergeDoneMsg reports the outcome of a merge dialog action.
+type mergeDoneMsg struct {
+	entry  Entry
+	action mergeAction
+	err    error
+}
+
+// mergeDialog shows what merging a pull request depends on and runs a chosen merge action.
+type mergeDialog struct {
+	entry   Entry
+	status  *pullrequest.MergeStatus
+	err     error
+	actions []mergeAction
+	cursor  int
+
+	confirming bool
+	running    bool
+	// run starts an action.
+	run func(action mergeAction) tea.Cmd
+}
+
+// isOwnTab reports whether the current category lists the user's own pull requests.
+func (m *ListModel) isOwnTab() bool {
+	return strings.Contains(m.Categories[m.CategoryIndex].Search().String(), string(pullrequest.AuthorMe))
+}
+
+// openMerge shows the merge dialog for the selected entry and loads its merge status.
+func (m *ListModel) openMerge() tea.Cmd {
+	merger, ok := m.fetcher.(pullrequest.Merger)
+	entry, selected := m.SelectedEntry()
+	if !ok || !selected {
+		return nil
+	}
+	if !m.isOwnTab() {
+		m.notice = "Merging is available in tabs listing your own pull requests, such as My PRs"
+		return nil
+	}
+
+	ctx := m.ctx
+	m.dialog = &mergeDialog{
+		entry: entry,
+		run: func(action mergeAction) tea.Cmd {
+			return func() tea.Msg {
+				ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+				defer cancel()
+				var err error
+				switch action.kind {
+				case mergeNow:
+					err = merger.Merge(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, action.method)
+				case enableAutoMerge:
+					err = merger.SetAutoMerge(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, action.method)
+				case disableAutoMerge:
+					err = merger.SetAutoMerge(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, "")
+				case updateBranch:
+					err = merger.UpdateBranch(ctx, entry.RepositoryNameWithOwner, entry.PrNumber)
+				}
+				return mergeDoneMsg{entry: entry, action: action, err: err}
+			}
+		},
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+		defer cancel()
+		status, err := merger.MergeStatus(ctx, entry.RepositoryNameWithOwner, entry.PrNumber)
+		return mergeStatusMsg{url: entry.URL, status: status, err: err}
+	}
+}
+
+// setStatus offers the actions the repository and the pull request allow.
+func (d *mergeDialog) setStatus(status *pullrequest.MergeStatus) {
+	d.status = status
+	d.actions = nil
+	for _, method := range status.Methods {
+		d.actions = append(d.actions, mergeAction{kind: mergeNow, method: method})
+	}
+	switch {
+	case status.AutoMergeMethod != "":
+		d.actions = append(d.actions, mergeAction{kind: disableAutoMerge})
+	case status.AutoMergeAllowed:
+		for _, method := range status.Methods {
+			d.actions = append(d.actions, mergeAction{kind: enableAutoMerge, method: method})
+		}
+	}
+	d.actions = append(d.actions, mergeAction{kind: updateBranch})
+}
+
+func (d *mergeDialog) Update(msg tea.Msg) (dialog, tea.Cmd) {
+	switch msg := msg.(type) {
+	case mergeStatusMsg:
+		if msg.url != d.entry.URL {
+			return d, nil
+		}
+		d.err = msg.err
+		if msg.err == nil {
+			d.setStatus(msg.status)
+		}
+		return d, nil
+	case mergeDoneMsg:
+		d.running, d.confirming = false, false
+		d.err = msg.err
+		return d, nil
+	case tea.KeyMsg:
+		switch {
+		case d.running:
+			return d, nil
+		case d.confirming:
+			switch msg.String() {
+			case "y", "enter":
+				d.running = true
+				return d, d.run(d.actions[d.cursor])
+			case "n", "esc":
+				d.confirming = false
+			}
+			return d, nil
+		}
+
+		switch msg.String() {
+		case "esc", "q":
+			return nil, nil
+		case "up", "k":
+			d.cursor = max(d.cursor-1, 0)
+		case "down", "j":
+			d.cursor = min(d.cursor+1, max(len(d.actions)-1, 0))
+		case "enter":
+			if len(d.actions) > 0 {
+				d.err = nil
+				d.confirming = true
+			}
+		}
+	}
+	return d, nil
+}
+
+func (d *mergeDialog) View() string {
+	lines := []string{
+		infoHeadingStyle.Render(fmt.Sprintf("Merge %s#%d — %s", d.entry.RepositoryNameWithOwner, d.entry.PrNumber, d.entry.Title)),
+		"",
+	}
+	if d.status == nil {
+		if d.err != nil {
+			lines = append(lines, checkStyles[model.CheckFailing].Render("⚠ Failed to load the merge status: "+errorDetail(d.err)),
+				"", infoDimStyle.Render("esc close"))
+		} else {
+			lines = append(lines, infoDimStyle.Render("Loading merge status…"))
+		}
+		return dialogStyle.Render(strings.Join(lines, "\n"))
+	}
+
+	lines = append(lines, d.statusLines()...)
+	lines = append(lines, "")
+	lines = append(lines, renderChecks(d.entry)...)
+	lines = append(lines, "")
+	for i, action := range d.actions {
+		if i == d.cursor {
+			lines = append(lines, selectedItemStyle.Render("▸ "+action.label()))
+		} else {
+			lines = append(lines, "  "+action.label())
+		}
+	}
+	lines = append(lines, "")
+
+	if d.err != nil {
+		lines = append(lines, checkStyles[model.CheckFailing].Render("⚠ "+errorDetail(d.err)))
+	}
+	switch {
+	case d.running:
+		lines = append(lines, infoDimStyle.Render(d.actions[d.cursor].label()+"…"))
+	case d.confirming:
+		action := d.actions[d.cursor]
+		if action.kind == mergeNow || action.kind == enableAutoMerge {
+			lines = append(lines, d.warnings()...)
+		}
+		lines = append(lines, infoHeadingStyle.Render(fmt.Sprintf("%s %s#%d? ", action.label(), d.entry.RepositoryNameWithOwner, d.entry.PrNumber))+
+			infoDimStyle.Render("y confirm · n back"))
+	default:
+		lines = append(lines, infoDimStyle.Render("↑/↓ select · enter choose · esc cancel"))
+	}
+	return dialogStyle.Render(strings.Join(lines, "\n"))
+}
+
+// statusLines describes the mergeable state and auto-merge of the pull request.
+func (d *mergeDialog) statusLines() []string {
+	var mergeable string
+	switch d.status.Mergeable {
+	case "MERGEABLE":
+		mergeable = checkStyles[model.CheckPassing].Render("✓ mergeable")
+	case "CONFLICTING":
+		mergeable = checkStyles[model.CheckFailing].Render("✗ conflicts with the base branch")
+	default:
+		mergeable = checkStyles[model.CheckPending].Render("● not computed yet")
+	}
+	if state, ok := mergeStates[d.status.MergeStateStatus]; ok {
+		mergeable += infoDimStyle.Render(" · " + state)
+	}
+
+	autoMerge := "not allowed in this repository"
+	switch {
+	case d.status.AutoMergeMethod != "":
+		autoMerge = checkStyles[model.CheckPassing].Render("enabled (" + mergeMethods[d.status.AutoMergeMethod].name + ")")
+	case d.status.AutoMergeAllowed:
+		autoMerge = "disabled"
+	}
+	return []string{
+		infoHeadingStyle.Render("Mergeable: ") + mergeable,
+		infoHeadingStyle.Render("Auto-merge: ") + autoMerge,
+	}
+}
+
+// warnings lists what should make the user think twice before merging.
+func (d *mergeDialog) warnings() []string {
+	var warnings []string
+	if d.entry.CheckState == model.CheckFailing {
+		warning := "⚠ checks are failing"
+		if len(d.entry.FailingChecks) > 0 {
+			warning += ": " + strings.Join(d.entry.FailingChecks, ", ")
+		}
+		warnings = append(warnings, warning)
+	}
+	if d.status.Mergeable == "CONFLICTING" {
+		warnings = append(warnings, "⚠ the branch has conflicts")
+	}
+	if d.status.MergeStateStatus == "BLOCKED" {
+		warnings = append(warnings, "⚠ branch protection blocks merging")
+	}
+	for i, warning := range warnings {
+		warnings[i] = checkStyles[model.CheckFailing].Render(warning)
+	}
+	return warnings
+}
+
+// merged updates the list after a merge action: merged pull requests leave
+// the tabs of open pull requests, and the state of the others is reloaded.
+func (m *ListModel) merged(msg mergeDoneMsg) tea.Cmd {
+	m.notice = fmt.Sprintf("✓ %s %s#%d", msg.action.done(), msg.entry.RepositoryNameWithOwner, msg.entry.PrNumber)
+	if msg.action.kind == mergeNow {
+		for i, c := range m.Categories {
+			if strings.Contains(c.Search().String(), string(pullrequest.StateOpen)) {
+				m.removePullRequest(i, msg.entry.URL)
+			}
+		}
+	}
+	return m.reloadEntryCmd(msg.entry)
+}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// pickerHeight is how many options a picker shows at once.
+const pickerHeight = 10
+
+// pickerItem is an option of a picker.
+type pickerItem struct {
+	// value is what picking the option returns, and what the filter matches.
+	value string
+	// detail is shown dimmed after the value.
+	detail string
+	// style renders the value, e.g. in the colour of a label.
+	style lipgloss.Style
+}
+
+// pickerState tells whether a picker is still open.
+type pickerState int
+
+const (
+	pickerOpen pickerState = iota
+	pickerDone
+	pickerCancelled
+)
+
+// picker narrows a list of options down with a fuzzy filter and picks one or,
+// with multi set, several of them.
+type picker struct {
+	items  []pickerItem
+	multi  bool
+	filter textinput.Model
+	// matches are the indexes of the items matching the filter, best first.
+	matches []int
+	cursor  int
+	// offset is the first match shown.
+	offset   int
+	selected map[string]bool
+	// toggled is set once an option was toggled in a multi-select picker.
+	toggled bool
+}
+
+// newPicker returns a focused picker of items with the values in selected picked already.
+func newPicker(items []pickerItem, multi bool, selected []string) *picker {
+	filter := textinput.New()
+	filter.Prompt = "› "
+	filter.Placeholder = "type to filter"
+	filter.Width = 40
+	filter.Focus()
+
+	p := &picker{items: items, multi: multi, filter: filter, selected: map[string]bool{}}
+	for _, value := range selected {
+		p.selected[value] = true
+	}
+	p.match()
+	return p
+}
+
+// match filters the items with the text typed so far.
+func (p *picker) match() {
+	p.matches = p.matches[:0]
+	if pattern := p.filter.Value(); pattern != "" {
+		values := make([]string, len(p.items))
+		for i, item := range p.items {
+			values[i] = item.value
+		}
+		for _, match := range fuzzy.Find(pattern, values) {
+			p.matches = append(p.matches, match.Index)
+		}
+	} else {
+		for i := range p.items {
+			p.matches = append(p.matches, i)
+		}
+	}
+	p.cursor, p.offset = 0, 0
+}
+
+// picked returns the picked values in the order of the items.
+func (p *picker) picked() []string {
+	var values []string
+	for _, item := range p.items {
+		if p.selected[item.value] {
+			values = append(values, item.value)
+		}
+	}
+	return values
+}
+
+// Update handles a message and reports whether the picker is done. Enter
+// picks the option under the cursor in a single-select picker, and in a
+// multi-select one where nothing was toggled.
+func (p *picker) Update(msg tea.Msg) (pickerState, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		p.filter, cmd = p.filter.Update(msg)
+		return pickerOpen, cmd
+	}
+
+	switch key.String() {
+	case "esc":
+		return pickerCancelled, nil
+	case "enter":
+		if !p.multi {
+			p.selected = map[string]bool{}
+		}
+		if (!p.multi || !p.toggled && len(p.picked()) == 0) && len(p.matches) > 0 {
+			p.selected[p.items[p.matches[p.cursor]].value] = true
+		}
+		return pickerDone, nil
+	case "up", "ctrl+p":
+		p.cursor = max(p.cursor-1, 0)
+	case "down", "ctrl+n":
+		p.cursor = min(p.cursor+1, max(len(p.matches)-1, 0))
+	case "tab":
+		if p.multi && len(p.matches) > 0 {
+			value := p.items[p.matches[p.cursor]].value
+			p.selected[value] = !p.selected[value]
+			p.toggled = true
+		}
+	default:
+		var cmd tea.Cmd
+		before := p.filter.Value()
+		p.filter, cmd = p.filter.Update(msg)
+		if p.filter.Value() != before {
+			p.match()
+		}
+		return pickerOpen, cmd
+	}
+
+	// 커서가 보이도록 스크롤
+	p.offset = min(p.offset, p.cursor)
+	p.offset = max(p.offset, p.cursor-pickerHeight+1)
+	return pickerOpen, nil
+}
+
+func (p *picker) View() string {
+	lines := []string{p.filter.View(), ""}
+	if len(p.matches) == 0 {
+		lines = append(lines, infoDimStyle.Render("  no matches"))
+	}
+	end := min(p.offset+pickerHeight, len(p.matches))
+	for i := p.offset; i < end; i++ {
+		item := p.items[p.matches[i]]
+		line := item.style.Render(item.value)
+		if item.detail != "" {
+			line += infoDimStyle.Render("  " + item.detail)
+		}
+		if p.multi {
+			check := "[ ] "
+			if p.selected[item.value] {
+				check = "[x] "
+			}
+			line = check + line
+		}
+		if i == p.cursor {
+			line = selectedItemStyle.Render("▸ ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	if len(p.matches) > pickerHeight {
+		lines = append(lines, infoDimStyle.Render(fmt.Sprintf("  %d/%d", p.cursor+1, len(p.matches))))
+	}
+
+	help := "↑/↓ select · enter pick · esc cancel"
+	if p.multi {
+		help = "↑/↓ select · tab toggle · enter confirm · esc cancel"
+	}
+	lines = append(lines, "", infoDimStyle.Render(help))
+	return strings.Join(lines, "\n")
+}
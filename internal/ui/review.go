@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+// reviewEvents describes every review verdict.
+var reviewEvents = map[pullrequest.ReviewEvent]struct {
+	verb, done string
+	style      lipgloss.Style
+}{
+	pullrequest.ReviewApprove:        {"Approve", "Approved", checkStyles[model.CheckPassing]},
+	pullrequest.ReviewComment:        {"Comment on", "Commented on", lipgloss.NewStyle()},
+	pullrequest.ReviewRequestChanges: {"Request changes on", "Requested changes on", checkStyles[model.CheckFailing]},
+}
+
+// reviewSubmittedMsg reports the outcome of submitting a review.
+type reviewSubmittedMsg struct {
+	entry Entry
+	event pullrequest.ReviewEvent
+	// comments is the number of line comments submitted with the review.
+	comments int
+	err      error
+}
+
+// reviewDialog writes a review body, confirms it and submits the review.
+type reviewDialog struct {
+	entry Entry
+	event pullrequest.ReviewEvent
+	// comments are the pending line comments submitted with the review.
+	comments   []pullrequest.DraftComment
+	body       textarea.Model
+	confirming bool
+	submitting bool
+	err        error
+	// submit starts submitting the review with a body.
+	submit func(body string) tea.Cmd
+}
+
+// openReview shows the dialog reviewing the selected entry with event. From
+// the diff it reviews the pull request shown, along with its pending comments.
+func (m *ListModel) openReview(event pullrequest.ReviewEvent) tea.Cmd {
+	submitter, ok := m.fetcher.(pullrequest.ReviewSubmitter)
+	entry, selected := m.SelectedEntry()
+	var comments []pullrequest.DraftComment
+	if m.diff != nil {
+		entry, selected = m.diff.entry, true
+		comments = slices.Clone(m.diff.pending)
+	}
+	if !ok || !selected {
+		return nil
+	}
+
+	body := textarea.New()
+	body.Placeholder = "Leave a comment (ctrl+e opens $EDITOR)"
+	body.SetWidth(min(m.width-docStyle.GetHorizontalFrameSize()-dialogStyle.GetHorizontalFrameSize(), 100))
+	body.SetHeight(8)
+	cmd := body.Focus()
+
+	ctx := m.ctx
+	m.dialog = &reviewDialog{
+		entry:    entry,
+		event:    event,
+		comments: comments,
+		body:     body,
+		submit: func(text string) tea.Cmd {
+			return func() tea.Msg {
+				ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+				defer cancel()
+				var err error
+				if commenter, ok := submitter.(pullrequest.ReviewCommenter); ok && len(comments) > 0 {
+					err = commenter.SubmitReviewWithComments(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, event, text, comments)
+				} else {
+					err = submitter.SubmitReview(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, event, text)
+				}
+				return reviewSubmittedMsg{entry: entry, event: event, comments: len(comments), err: err}
+			}
+		},
+	}
+	return cmd
+}
+
+func (d *reviewDialog) Update(msg tea.Msg) (dialog, tea.Cmd) {
+	switch msg := msg.(type) {
+	case editorFinishedMsg:
+		d.err = msg.err
+		d.body.SetValue(msg.text)
+		return d, nil
+	case reviewSubmittedMsg:
+		d.submitting, d.confirming = false, false
+		d.err = msg.err
+		return d, nil
+	case tea.KeyMsg:
+		switch {
+		case d.submitting:
+			return d, nil
+		case d.confirming:
+			switch msg.String() {
+			case "y", "enter":
+				d.submitting = true
+				return d, d.submit(strings.TrimSpace(d.body.Value()))
+			case "n", "esc":
+				d.confirming = false
+			}
+			return d, nil
+		}
+
+		switch msg.String() {
+		case "esc":
+			return nil, nil
+		case "ctrl+s":
+			if d.event != pullrequest.ReviewApprove && strings.TrimSpace(d.body.Value()) == "" {
+				d.err = errors.New("a comment is required")
+				return d, nil
+			}
+			d.err = nil
+			d.confirming = true
+			return d, nil
+		case "ctrl+e":
+			return d, editCmd(d.body.Value())
+		}
+	}
+
+	var cmd tea.Cmd
+	d.body, cmd = d.body.Update(msg)
+	return d, cmd
+}
+
+func (d *reviewDialog) View() string {
+	event := reviewEvents[d.event]
+	lines := []string{
+		event.style.Bold(true).Render(event.verb) + infoHeadingStyle.Render(fmt.Sprintf(" %s#%d — %s", d.entry.RepositoryNameWithOwner, d.entry.PrNumber, d.entry.Title)),
+	}
+	if len(d.comments) > 0 {
+		lines = append(lines, "", infoDimStyle.Render(fmt.Sprintf("with %s:", plural(len(d.comments), "line comment"))))
+		for _, comment := range d.comments {
+			lines = append(lines, diffPendingStyle.Render("  "+draftLocation(comment)+" ")+firstLine(comment.Body))
+		}
+	}
+	lines = append(lines, "", d.body.View(), "")
+	if d.err != nil {
+		lines = append(lines, checkStyles[model.CheckFailing].Render("⚠ "+errorDetail(d.err)))
+	}
+	switch {
+	case d.submitting:
+		lines = append(lines, infoDimStyle.Render("Submitting review…"))
+	case d.confirming:
+		lines = append(lines, infoHeadingStyle.Render(fmt.Sprintf("%s %s#%d? ", event.verb, d.entry.RepositoryNameWithOwner, d.entry.PrNumber))+infoDimStyle.Render("y submit · n keep editing"))
+	default:
+		lines = append(lines, infoDimStyle.Render("ctrl+s submit · ctrl+e edit in $EDITOR · esc cancel"))
+	}
+	return dialogStyle.Render(strings.Join(lines, "\n"))
+}
+
+// reviewed updates the list after a review was submitted: it leaves the
+// review requests, and its state is reloaded. Submitted line comments leave
+// the pending review of the diff and show up as threads.
+func (m *ListModel) reviewed(msg reviewSubmittedMsg) tea.Cmd {
+	m.notice = fmt.Sprintf("✓ %s %s#%d", reviewEvents[msg.event].done, msg.entry.RepositoryNameWithOwner, msg.entry.PrNumber)
+	if msg.comments > 0 {
+		m.notice += " with " + plural(msg.comments, "comment")
+	}
+	for i, c := range m.Categories {
+		if strings.Contains(c.Search().String(), string(pullrequest.ReviewRequestedMe)) {
+			m.removePullRequest(i, msg.entry.URL)
+		}
+	}
+	cmds := []tea.Cmd{m.reloadEntryCmd(msg.entry)}
+	if m.diff != nil && m.diff.entry.URL == msg.entry.URL && msg.comments > 0 {
+		m.diff.clearPending()
+		cmds = append(cmds, m.loadDiffThreadsCmd(msg.entry))
+	}
+	return tea.Batch(cmds...)
+}
+
+// pendingCommentMsg adds a comment to the pending review of the diff of the
+// pull request at url.
+type pendingCommentMsg struct {
+	url     string
+	comment pullrequest.DraftComment
+}
+
+// lineCommentDialog writes a comment on lines of the diff, kept in the
+// pending review until it is submitted.
+type lineCommentDialog struct {
+	entry   Entry
+	comment pullrequest.DraftComment
+	body    textarea.Model
+	err     error
+}
+
+// openLineComment shows the dialog commenting on the lines selected in the diff.
+func (m *ListModel) openLineComment() tea.Cmd {
+	if _, ok := m.fetcher.(pullrequest.ReviewCommenter); !ok || m.diff == nil {
+		return nil
+	}
+	comment, ok := m.diff.draftComment()
+	if !ok {
+		m.notice = "Select lines of a single file, not headers or comments, to comment on"
+		return nil
+	}
+
+	body := textarea.New()
+	body.Placeholder = "Comment on these lines (ctrl+e opens $EDITOR)"
+	body.SetWidth(min(m.width-docStyle.GetHorizontalFrameSize()-dialogStyle.GetHorizontalFrameSize(), 100))
+	body.SetHeight(6)
+	cmd := body.Focus()
+
+	m.dialog = &lineCommentDialog{entry: m.diff.entry, comment: comment, body: body}
+	return cmd
+}
+
+func (d *lineCommentDialog) Update(msg tea.Msg) (dialog, tea.Cmd) {
+	switch msg := msg.(type) {
+	case editorFinishedMsg:
+		d.err = msg.err
+		d.body.SetValue(msg.text)
+		return d, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return nil, nil
+		case "ctrl+s":
+			text := strings.TrimSpace(d.body.Value())
+			if text == "" {
+				d.err = errors.New("a comment is required")
+				return d, nil
+			}
+			comment := d.comment
+			comment.Body = text
+			url := d.entry.URL
+			return nil, func() tea.Msg { return pendingCommentMsg{url: url, comment: comment} }
+		case "ctrl+e":
+			return d, editCmd(d.body.Value())
+		}
+	}
+
+	var cmd tea.Cmd
+	d.body, cmd = d.body.Update(msg)
+	return d, cmd
+}
+
+func (d *lineCommentDialog) View() string {
+	lines := []string{
+		infoHeadingStyle.Render(fmt.Sprintf("Comment on %s in %s#%d", draftLocation(d.comment), d.entry.RepositoryNameWithOwner, d.entry.PrNumber)),
+		"",
+		d.body.View(),
+		"",
+	}
+	if d.err != nil {
+		lines = append(lines, checkStyles[model.CheckFailing].Render("⚠ "+errorDetail(d.err)))
+	}
+	lines = append(lines, infoDimStyle.Render("ctrl+s add to review · ctrl+e edit in $EDITOR · esc cancel"))
+	return dialogStyle.Render(strings.Join(lines, "\n"))
+}
+
+// addPendingComment adds a written line comment to the pending review of the diff.
+func (m *ListModel) addPendingComment(msg pendingCommentMsg) {
+	if m.diff == nil || m.diff.entry.URL != msg.url {
+		return
+	}
+	m.diff.addPending(msg.comment)
+	m.notice = fmt.Sprintf("✓ Added a comment on %s to your review (%d pending) · a/m/x submits it", draftLocation(msg.comment), len(m.diff.pending))
+}
+
+// draftLocation is the file and lines a comment is on, like path:10-12.
+func draftLocation(comment pullrequest.DraftComment) string {
+	if comment.StartLine != 0 && (comment.StartLine != comment.Line || comment.StartSide != comment.Side) {
+		return fmt.Sprintf("%s:%d-%d", comment.Path, comment.StartLine, comment.Line)
+	}
+	return fmt.Sprintf("%s:%d", comment.Path, comment.Line)
+}
+
+// plural counts n things named noun, like "2 comments".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+// reviewerChange asks reviewers for a review, or withdraws the request when remove is set.
+type reviewerChange struct {
+	reviewers []string
+	remove    bool
+}
+
+func (c reviewerChange) done() string {
+	if c.remove {
+		return "Removed " + strings.Join(c.reviewers, ", ") + " from"
+	}
+	return "Requested a review from " + strings.Join(c.reviewers, ", ") + " on"
+}
+
+// reviewerCandidatesMsg carries the collaborators and teams a review can be requested from.
+type reviewerCandidatesMsg struct {
+	url        string
+	candidates []pullrequest.Candidate
+	err        error
+}
+
+// reviewersChangedMsg reports the outcome of a reviewer change.
+type reviewersChangedMsg struct {
+	entry  Entry
+	change reviewerChange
+	err    error
+}
+
+// reviewersDialog lists the reviewers of a pull request, requests reviews
+// from people picked among the candidates and removes or repeats requests.
+type reviewersDialog struct {
+	entry     Entry
+	reviewers []model.Reviewer
+	cursor    int
+
+	candidates       []pullrequest.Candidate
+	candidatesLoaded bool
+	candidatesErr    error
+	// picker is shown while choosing whom to request a review from.
+	picker *picker
+
+	running bool
+	err     error
+	// run starts a change.
+	run func(change reviewerChange) tea.Cmd
+}
+
+// openReviewers shows the reviewers of the selected entry and loads the candidates.
+func (m *ListModel) openReviewers() tea.Cmd {
+	editor, ok := m.fetcher.(pullrequest.ReviewerEditor)
+	entry, selected := m.SelectedEntry()
+	if !ok || !selected {
+		return nil
+	}
+
+	ctx := m.ctx
+	m.dialog = &reviewersDialog{
+		entry:     entry,
+		reviewers: append([]model.Reviewer(nil), entry.Reviewers...),
+		run: func(change reviewerChange) tea.Cmd {
+			return func() tea.Msg {
+				ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+				defer cancel()
+				var err error
+				if change.remove {
+					err = editor.RemoveReviewers(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, change.reviewers)
+				} else {
+					err = editor.RequestReviews(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, change.reviewers)
+				}
+				return reviewersChangedMsg{entry: entry, change: change, err: err}
+			}
+		},
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+		defer cancel()
+		candidates, err := editor.ReviewerCandidates(ctx, entry.RepositoryNameWithOwner)
+		return reviewerCandidatesMsg{url: entry.URL, candidates: candidates, err: err}
+	}
+}
+
+// apply updates the listed reviewers once a change succeeded.
+func (d *reviewersDialog) apply(change reviewerChange) {
+	for _, name := range change.reviewers {
+		i := d.index(name)
+		switch {
+		case change.remove && i >= 0 && d.reviewers[i].State == "":
+			d.reviewers = append(d.reviewers[:i], d.reviewers[i+1:]...)
+		case i >= 0:
+			d.reviewers[i].Requested = !change.remove
+		case !change.remove:
+			d.reviewers = append(d.reviewers, model.Reviewer{Name: name, Requested: true})
+		}
+	}
+	d.cursor = min(d.cursor, max(len(d.reviewers)-1, 0))
+}
+
+func (d *reviewersDialog) index(name string) int {
+	for i, reviewer := range d.reviewers {
+		if reviewer.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// openPicker offers the candidates that are not asked for a review yet.
+func (d *reviewersDialog) openPicker() {
+	var items []pickerItem
+	for _, candidate := range d.candidates {
+		if candidate.Login == d.entry.Author {
+			continue
+		}
+		if i := d.index(candidate.Login); i >= 0 && d.reviewers[i].Requested {
+			continue
+		}
+		item := pickerItem{value: candidate.Login}
+		if candidate.Team {
+			item.detail = "team " + candidate.Name
+		}
+		if i := d.index(candidate.Login); i >= 0 {
+			item.detail = "reviewed"
+		}
+		items = append(items, item)
+	}
+	d.picker = newPicker(items, true, nil)
+}
+
+func (d *reviewersDialog) start(change reviewerChange) tea.Cmd {
+	d.err = nil
+	d.running = true
+	return d.run(change)
+}
+
+func (d *reviewersDialog) Update(msg tea.Msg) (dialog, tea.Cmd) {
+	switch msg := msg.(type) {
+	case reviewerCandidatesMsg:
+		if msg.url == d.entry.URL {
+			d.candidates, d.candidatesErr = msg.candidates, msg.err
+			d.candidatesLoaded = msg.err == nil
+		}
+		return d, nil
+	case reviewersChangedMsg:
+		d.running = false
+		d.err = msg.err
+		if msg.err == nil {
+			d.apply(msg.change)
+		}
+		return d, nil
+	case tea.KeyMsg:
+		if d.running {
+			return d, nil
+		}
+		if d.picker != nil {
+			state, cmd := d.picker.Update(msg)
+			switch state {
+			case pickerDone:
+				picked := d.picker.picked()
+				d.picker = nil
+				if len(picked) > 0 {
+					return d, d.start(reviewerChange{reviewers: picked})
+				}
+			case pickerCancelled:
+				d.picker = nil
+			}
+			return d, cmd
+		}
+
+		var selected *model.Reviewer
+		if d.cursor < len(d.reviewers) {
+			selected = &d.reviewers[d.cursor]
+		}
+		switch msg.String() {
+		case "esc", "q":
+			return nil, nil
+		case "up", "k":
+			d.cursor = max(d.cursor-1, 0)
+		case "down", "j":
+			d.cursor = min(d.cursor+1, max(len(d.reviewers)-1, 0))
+		case "+", "a":
+			if d.candidatesLoaded {
+				d.openPicker()
+				return d, textinput.Blink
+			}
+		case "d", "x":
+			if selected != nil && selected.Requested {
+				return d, d.start(reviewerChange{reviewers: []string{selected.Name}, remove: true})
+			}
+		case "r":
+			if selected != nil && selected.State != "" {
+				return d, d.start(reviewerChange{reviewers: []string{selected.Name}})
+			}
+		}
+		return d, nil
+	}
+
+	// 선택 창의 커서 깜빡임 전달
+	if d.picker != nil {
+		_, cmd := d.picker.Update(msg)
+		return d, cmd
+	}
+	return d, nil
+}
+
+func (d *reviewersDialog) View() string {
+	lines := []string{
+		infoHeadingStyle.Render(fmt.Sprintf("Reviewers of %s#%d — %s", d.entry.RepositoryNameWithOwner, d.entry.PrNumber, d.entry.Title)),
+		"",
+	}
+	if d.picker != nil {
+		lines = append(lines, infoHeadingStyle.Render("Request a review from"), d.picker.View())
+		return dialogStyle.Render(strings.Join(lines, "\n"))
+	}
+
+	if len(d.reviewers) == 0 {
+		lines = append(lines, checkStyles[model.CheckNone].Render("  no reviewers"))
+	}
+	for i, reviewer := range d.reviewers {
+		var states []string
+		if state, ok := reviewStates[reviewer.State]; ok {
+			states = append(states, state.style.Render(state.label))
+		}
+		if reviewer.Requested {
+			states = append(states, checkStyles[model.CheckPending].Render("● review requested"))
+		}
+		line := reviewer.Name + "  " + strings.Join(states, ", ")
+		if i == d.cursor {
+			line = selectedItemStyle.Render("▸ ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "")
+
+	switch {
+	case d.candidatesErr != nil:
+		lines = append(lines, checkStyles[model.CheckFailing].Render("⚠ Failed to load collaborators: "+errorDetail(d.candidatesErr)))
+	case !d.candidatesLoaded:
+		lines = append(lines, infoDimStyle.Render("Loading collaborators…"))
+	}
+	if d.err != nil {
+		lines = append(lines, checkStyles[model.CheckFailing].Render("⚠ "+errorDetail(d.err)))
+	}
+	if d.running {
+		lines = append(lines, infoDimStyle.Render("Updating reviewers…"))
+	} else {
+		lines = append(lines, infoDimStyle.Render("+ request review · d remove request · r re-request · esc close"))
+	}
+	return dialogStyle.Render(strings.Join(lines, "\n"))
+}
+
+// reviewersChanged reports a reviewer change and reloads the pull request.
+func (m *ListModel) reviewersChanged(msg reviewersChangedMsg) tea.Cmd {
+	m.notice = fmt.Sprintf("✓ %s %s#%d", msg.change.done(), msg.entry.RepositoryNameWithOwner, msg.entry.PrNumber)
+	return m.reloadEntryCmd(msg.entry)
+}
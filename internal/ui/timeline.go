@@ -0,0 +1,472 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+	"github.com/jinwoo1225/gh-rr/internal/utils"
+)
+
+var (
+	timelineHeaderStyle = lipgloss.NewStyle().Bold(true)
+
+	// timelineThreadStyle sets the comments of a review thread apart.
+	timelineThreadStyle = lipgloss.NewStyle().
+				PaddingLeft(1).
+				Border(lipgloss.NormalBorder(), false, false, false, true).
+				BorderForeground(lipgloss.Color("240"))
+)
+
+// timelineLoadedMsg carries the conversation on the pull request at url.
+type timelineLoadedMsg struct {
+	url      string
+	timeline *pullrequest.Timeline
+	err      error
+}
+
+// timelineModel shows the conversation on a pull request in chronological order.
+type timelineModel struct {
+	entry   Entry
+	loading bool
+	err     error
+	items   []pullrequest.TimelineItem
+	// truncated lists what the pull request has more of than items holds.
+	truncated []pullrequest.Truncation
+
+	// bodies are the rendered items without their headers, for the width they were rendered at.
+	bodies      []string
+	bodiesWidth int
+	// itemStarts is the first line of every item; item is the current one.
+	itemStarts []int
+	item       int
+
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func newTimelineModel(entry Entry, width, height int) *timelineModel {
+	t := &timelineModel{entry: entry, loading: true}
+	t.setSize(width, height)
+	return t
+}
+
+// loadTimelineCmd fetches the conversation on entry.
+func (m *ListModel) loadTimelineCmd(fetcher pullrequest.TimelineFetcher, entry Entry) tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+		defer cancel()
+		timeline, err := fetcher.Timeline(ctx, entry.RepositoryNameWithOwner, entry.PrNumber)
+		return timelineLoadedMsg{url: entry.URL, timeline: timeline, err: err}
+	}
+}
+
+// reloadTimelineCmd loads the open timeline again, e.g. after commenting on it.
+func (m *ListModel) reloadTimelineCmd() tea.Cmd {
+	fetcher, ok := m.fetcher.(pullrequest.TimelineFetcher)
+	if !ok || m.timeline == nil {
+		return nil
+	}
+	m.timeline.loading = true
+	return tea.Batch(m.loadTimelineCmd(fetcher, m.timeline.entry), m.spinCmd())
+}
+
+// setSize fits the timeline into width by height cells: a header line, the
+// conversation and a help line.
+func (t *timelineModel) setSize(width, height int) {
+	t.width, t.height = width, height
+	t.viewport.Width = width
+	t.viewport.Height = max(height-2, 1)
+	if t.items != nil && t.bodiesWidth != width {
+		t.render()
+	}
+}
+
+// setTimeline renders the loaded conversation. A failed reload keeps the
+// conversation shown before.
+func (t *timelineModel) setTimeline(timeline *pullrequest.Timeline, err error) {
+	t.loading = false
+	t.err = err
+	if err != nil {
+		return
+	}
+	t.items, t.truncated = timeline.Items, timeline.Truncated
+	t.item = min(t.item, max(len(t.items)-1, 0))
+	t.render()
+}
+
+// render renders the body of every item for the current width.
+func (t *timelineModel) render() {
+	t.bodiesWidth = t.width
+	t.bodies = make([]string, len(t.items))
+	for i, item := range t.items {
+		t.bodies[i] = renderTimelineBody(item, t.width)
+	}
+	t.refresh()
+}
+
+// refresh puts the items into the viewport, marking the current one, below a
+// notice of what was left out.
+func (t *timelineModel) refresh() {
+	now := time.Now()
+	var lines []string
+	if notice := truncationNotice(t.truncated); notice != "" {
+		lines = append(lines, checkStyles[model.CheckPending].Render(notice), "")
+	}
+	t.itemStarts = t.itemStarts[:0]
+	for i, item := range t.items {
+		t.itemStarts = append(t.itemStarts, len(lines))
+		header := renderTimelineHeader(item, now)
+		if i == t.item {
+			header = selectedItemStyle.Render("▸ ") + header
+		} else {
+			header = "  " + header
+		}
+		lines = append(lines, header)
+		if t.bodies[i] != "" {
+			lines = append(lines, strings.Split(t.bodies[i], "\n")...)
+		}
+		lines = append(lines, "")
+	}
+	t.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// truncationNotice tells what a timeline leaves out, or is empty when it holds everything.
+func truncationNotice(truncated []pullrequest.Truncation) string {
+	if len(truncated) == 0 {
+		return ""
+	}
+	counts := make([]string, len(truncated))
+	for i, truncation := range truncated {
+		counts[i] = fmt.Sprintf("%d of %d %s", truncation.Shown, truncation.Total, truncation.What)
+	}
+	return "⚠ showing " + strings.Join(counts, ", ") + " · open the pull request on GitHub for the rest"
+}
+
+// renderTimelineHeader tells who did what and when.
+func renderTimelineHeader(item pullrequest.TimelineItem, now time.Time) string {
+	age := infoDimStyle.Render(" · " + utils.HumanizeDuration(int(now.Sub(item.CreatedAt).Seconds())) + " ago")
+	author := timelineHeaderStyle.Render(item.Author)
+	switch item.Kind {
+	case pullrequest.TimelineDescription:
+		return author + " opened this pull request" + age
+	case pullrequest.TimelineReview:
+		state, ok := reviewStates[item.State]
+		if !ok {
+			return author + " reviewed" + age
+		}
+		return author + " " + state.style.Render(state.label) + age
+	case pullrequest.TimelineThread:
+		thread := item.Thread
+		header := author + " commented on " + timelineHeaderStyle.Render(threadLocation(thread))
+		if thread.Resolved {
+			header += " " + checkStyles[model.CheckPassing].Render("✓ resolved")
+		}
+		if thread.Outdated {
+			header += " " + checkStyles[model.CheckNone].Render("outdated")
+		}
+		return header + age
+	default:
+		return author + " commented" + age
+	}
+}
+
+// threadLocation is the file and line or range a thread is on.
+func threadLocation(thread *pullrequest.Thread) string {
+	line := thread.Line
+	if line == 0 {
+		line = thread.OriginalLine
+	}
+	switch {
+	case line == 0:
+		return thread.Path
+	case thread.StartLine != 0 && thread.StartLine != line:
+		return fmt.Sprintf("%s:%d-%d", thread.Path, thread.StartLine, line)
+	default:
+		return fmt.Sprintf("%s:%d", thread.Path, line)
+	}
+}
+
+// renderTimelineBody renders the Markdown of an item, or every comment of a thread.
+func renderTimelineBody(item pullrequest.TimelineItem, width int) string {
+	if item.Kind != pullrequest.TimelineThread {
+		if item.Kind != pullrequest.TimelineDescription && strings.TrimSpace(item.Body) == "" {
+			return ""
+		}
+		return lipgloss.NewStyle().PaddingLeft(2).Render(renderMarkdown(item.Body, width-2))
+	}
+
+	frame := 2 + timelineThreadStyle.GetHorizontalFrameSize()
+	var comments []string
+	for i, comment := range item.Thread.Comments {
+		if i > 0 {
+			comments = append(comments, "", timelineHeaderStyle.Render(comment.Author)+infoDimStyle.Render(" replied"))
+		}
+		comments = append(comments, renderMarkdown(comment.Body, width-frame))
+	}
+	return lipgloss.NewStyle().PaddingLeft(2).Render(timelineThreadStyle.Render(strings.Join(comments, "\n")))
+}
+
+// itemAt is the index of the item line belongs to.
+func (t *timelineModel) itemAt(line int) int {
+	current := 0
+	for i, start := range t.itemStarts {
+		if start <= line {
+			current = i
+		}
+	}
+	return current
+}
+
+// setItem makes item the current one.
+func (t *timelineModel) setItem(item int) {
+	if item != t.item {
+		t.item = item
+		t.refresh()
+	}
+}
+
+func (t *timelineModel) gotoItem(i int) {
+	if len(t.itemStarts) == 0 {
+		return
+	}
+	t.setItem((i + len(t.itemStarts)) % len(t.itemStarts))
+	t.viewport.SetYOffset(t.itemStarts[t.item])
+}
+
+// currentThread is the review thread under the cursor, if it is one.
+func (t *timelineModel) currentThread() *pullrequest.Thread {
+	if t.item < len(t.items) {
+		return t.items[t.item].Thread
+	}
+	return nil
+}
+
+// timelineAction is what a key in the timeline asks the list to do.
+type timelineAction int
+
+const (
+	timelineNone timelineAction = iota
+	timelineClose
+	timelineComment
+	timelineReply
+)
+
+// Update handles a key while the timeline is shown.
+func (t *timelineModel) Update(msg tea.KeyMsg) (timelineAction, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return timelineClose, nil
+	case "c":
+		if t.items != nil {
+			return timelineComment, nil
+		}
+	case "r":
+		if t.currentThread() != nil {
+			return timelineReply, nil
+		}
+	case "]":
+		t.gotoItem(t.item + 1)
+	case "[":
+		t.gotoItem(t.item - 1)
+	case "home", "g":
+		t.viewport.GotoTop()
+		t.setItem(0)
+	case "end", "G":
+		t.viewport.GotoBottom()
+		t.setItem(max(len(t.items)-1, 0))
+	default:
+		offset := t.viewport.YOffset
+		var cmd tea.Cmd
+		t.viewport, cmd = t.viewport.Update(msg)
+		if t.viewport.YOffset != offset {
+			t.setItem(t.itemAt(t.viewport.YOffset))
+		}
+		return timelineNone, cmd
+	}
+	return timelineNone, nil
+}
+
+func (t *timelineModel) View(spinner string) string {
+	title := infoHeadingStyle.Render(fmt.Sprintf("%s#%d — %s", t.entry.RepositoryNameWithOwner, t.entry.PrNumber, t.entry.Title))
+	switch {
+	case t.items == nil && t.loading:
+		return lipgloss.JoinVertical(lipgloss.Left, title, infoDimStyle.Render(spinner+" Loading conversation…"))
+	case t.items == nil && t.err != nil:
+		return lipgloss.JoinVertical(lipgloss.Left, title,
+			checkStyles[model.CheckFailing].Render("⚠ Failed to load conversation: "+errorDetail(t.err)),
+			infoDimStyle.Render("esc to go back"))
+	}
+
+	status := fmt.Sprintf("item %d/%d · %3.f%%", t.item+1, len(t.items), t.viewport.ScrollPercent()*100)
+	switch {
+	case t.loading:
+		status += " · " + spinner + " refreshing…"
+	case t.err != nil:
+		status += " · failed to refresh: " + errorDetail(t.err)
+	}
+	header := title + infoDimStyle.Render(" · "+status)
+	help := "↑/↓ scroll · [/] prev/next item · c comment · esc back"
+	if t.currentThread() != nil {
+		help = "↑/↓ scroll · [/] prev/next item · c comment · r reply to thread · esc back"
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, t.viewport.View(), infoDimStyle.Render(help))
+}
+
+// commentPostedMsg reports the outcome of posting a comment, or a reply when threadID is set.
+type commentPostedMsg struct {
+	entry    Entry
+	threadID string
+	err      error
+}
+
+// commentDialog writes a comment on a pull request or a reply to one of its threads.
+type commentDialog struct {
+	entry Entry
+	// thread is the review thread replied to, nil for a new comment.
+	thread     *pullrequest.Thread
+	body       textarea.Model
+	confirming bool
+	posting    bool
+	err        error
+	// post starts posting the comment with a body.
+	post func(body string) tea.Cmd
+}
+
+// openComment shows the dialog commenting on the pull request of the
+// timeline, replying to thread unless it is nil.
+func (m *ListModel) openComment(thread *pullrequest.Thread) tea.Cmd {
+	fetcher, ok := m.fetcher.(pullrequest.TimelineFetcher)
+	if !ok || m.timeline == nil {
+		return nil
+	}
+	entry := m.timeline.entry
+
+	body := textarea.New()
+	body.Placeholder = "Leave a comment (ctrl+e opens $EDITOR)"
+	body.SetWidth(min(m.width-docStyle.GetHorizontalFrameSize()-dialogStyle.GetHorizontalFrameSize(), 100))
+	body.SetHeight(8)
+	cmd := body.Focus()
+
+	ctx := m.ctx
+	m.dialog = &commentDialog{
+		entry:  entry,
+		thread: thread,
+		body:   body,
+		post: func(text string) tea.Cmd {
+			return func() tea.Msg {
+				ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+				defer cancel()
+				if thread != nil {
+					err := fetcher.ReplyToThread(ctx, thread.ID, text)
+					return commentPostedMsg{entry: entry, threadID: thread.ID, err: err}
+				}
+				err := fetcher.AddComment(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, text)
+				return commentPostedMsg{entry: entry, err: err}
+			}
+		},
+	}
+	return cmd
+}
+
+func (d *commentDialog) Update(msg tea.Msg) (dialog, tea.Cmd) {
+	switch msg := msg.(type) {
+	case editorFinishedMsg:
+		d.err = msg.err
+		d.body.SetValue(msg.text)
+		return d, nil
+	case commentPostedMsg:
+		d.posting, d.confirming = false, false
+		d.err = msg.err
+		return d, nil
+	case tea.KeyMsg:
+		switch {
+		case d.posting:
+			return d, nil
+		case d.confirming:
+			switch msg.String() {
+			case "y", "enter":
+				d.posting = true
+				return d, d.post(strings.TrimSpace(d.body.Value()))
+			case "n", "esc":
+				d.confirming = false
+			}
+			return d, nil
+		}
+
+		switch msg.String() {
+		case "esc":
+			return nil, nil
+		case "ctrl+s":
+			if strings.TrimSpace(d.body.Value()) == "" {
+				d.err = errors.New("a comment is required")
+				return d, nil
+			}
+			d.err = nil
+			d.confirming = true
+			return d, nil
+		case "ctrl+e":
+			return d, editCmd(d.body.Value())
+		}
+	}
+
+	var cmd tea.Cmd
+	d.body, cmd = d.body.Update(msg)
+	return d, cmd
+}
+
+func (d *commentDialog) View() string {
+	verb := "Comment on"
+	if d.thread != nil {
+		verb = "Reply on " + threadLocation(d.thread) + " in"
+	}
+	lines := []string{
+		infoHeadingStyle.Render(fmt.Sprintf("%s %s#%d — %s", verb, d.entry.RepositoryNameWithOwner, d.entry.PrNumber, d.entry.Title)),
+		"",
+	}
+	if d.thread != nil {
+		last := d.thread.Comments[len(d.thread.Comments)-1]
+		lines = append(lines, infoDimStyle.Render(last.Author+": "+firstLine(last.Body)), "")
+	}
+	lines = append(lines, d.body.View(), "")
+	if d.err != nil {
+		lines = append(lines, checkStyles[model.CheckFailing].Render("⚠ "+errorDetail(d.err)))
+	}
+	switch {
+	case d.posting:
+		lines = append(lines, infoDimStyle.Render("Posting…"))
+	case d.confirming:
+		lines = append(lines, infoHeadingStyle.Render("Post this comment? ")+infoDimStyle.Render("y post · n keep editing"))
+	default:
+		lines = append(lines, infoDimStyle.Render("ctrl+s post · ctrl+e edit in $EDITOR · esc cancel"))
+	}
+	return dialogStyle.Render(strings.Join(lines, "\n"))
+}
+
+// firstLine returns the first line of text.
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
+
+// commented reports a posted comment, reloading the timeline and the pull request.
+func (m *ListModel) commented(msg commentPostedMsg) tea.Cmd {
+	verb := "Commented on"
+	if msg.threadID != "" {
+		verb = "Replied on"
+	}
+	m.notice = fmt.Sprintf("✓ %s %s#%d", verb, msg.entry.RepositoryNameWithOwner, msg.entry.PrNumber)
+	return tea.Batch(m.reloadTimelineCmd(), m.reloadEntryCmd(msg.entry))
+}
@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+)
+
+// triageField is what a triage dialog edits.
+type triageField int
+
+const (
+	triageLabels triageField = iota
+	triageAssignees
+)
+
+func (f triageField) name() string {
+	if f == triageLabels {
+		return "Labels"
+	}
+	return "Assignees"
+}
+
+// triageOptionsMsg carries the labels or assignable users of a repository.
+type triageOptionsMsg struct {
+	url   string
+	items []pickerItem
+	err   error
+}
+
+// triagedMsg reports the outcome of adding and removing labels or assignees.
+type triagedMsg struct {
+	entry       Entry
+	field       triageField
+	add, remove []string
+	err         error
+}
+
+// triageDialog picks the labels or assignees of a pull request among those of
+// its repository and applies the difference.
+type triageDialog struct {
+	entry Entry
+	field triageField
+	// current are the labels or assignees the pull request has.
+	current []string
+	// picker is shown once the options are loaded.
+	picker *picker
+
+	running bool
+	err     error
+	// run starts adding and removing values.
+	run func(add, remove []string) tea.Cmd
+}
+
+// newTriageDialog returns a dialog editing field of entry, along with the
+// command loading the options.
+func (m *ListModel) newTriageDialog(triager pullrequest.Triager, entry Entry, field triageField) (*triageDialog, tea.Cmd) {
+	d := &triageDialog{entry: entry, field: field}
+	if field == triageLabels {
+		for _, label := range entry.Labels {
+			d.current = append(d.current, label.Name)
+		}
+	} else {
+		d.current = append(d.current, entry.Assignees...)
+	}
+
+	ctx := m.ctx
+	d.run = func(add, remove []string) tea.Cmd {
+		return func() tea.Msg {
+			ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+			defer cancel()
+			var err error
+			if field == triageLabels {
+				err = triager.EditLabels(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, add, remove)
+			} else {
+				err = triager.EditAssignees(ctx, entry.RepositoryNameWithOwner, entry.PrNumber, add, remove)
+			}
+			return triagedMsg{entry: entry, field: field, add: add, remove: remove, err: err}
+		}
+	}
+	load := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, category.DefaultTimeout)
+		defer cancel()
+		var items []pickerItem
+		if field == triageLabels {
+			labels, err := triager.Labels(ctx, entry.RepositoryNameWithOwner)
+			if err != nil {
+				return triageOptionsMsg{url: entry.URL, err: err}
+			}
+			for _, label := range labels {
+				items = append(items, pickerItem{value: label.Name, style: labelStyle(label.Color)})
+			}
+		} else {
+			logins, err := triager.AssignableUsers(ctx, entry.RepositoryNameWithOwner)
+			if err != nil {
+				return triageOptionsMsg{url: entry.URL, err: err}
+			}
+			for _, login := range logins {
+				items = append(items, pickerItem{value: login})
+			}
+		}
+		return triageOptionsMsg{url: entry.URL, items: items}
+	}
+	return d, load
+}
+
+// openTriage shows the picker of the labels or assignees of the selected entry.
+func (m *ListModel) openTriage(field triageField) tea.Cmd {
+	triager, ok := m.fetcher.(pullrequest.Triager)
+	entry, selected := m.SelectedEntry()
+	if !ok || !selected {
+		return nil
+	}
+	d, load := m.newTriageDialog(triager, entry, field)
+	m.dialog = d
+	return load
+}
+
+// assignMe assigns the selected entry to the authenticated user right away.
+func (m *ListModel) assignMe() tea.Cmd {
+	triager, ok := m.fetcher.(pullrequest.Triager)
+	entry, selected := m.SelectedEntry()
+	if !ok || !selected {
+		return nil
+	}
+	d, _ := m.newTriageDialog(triager, entry, triageAssignees)
+	d.running = true
+	m.dialog = d
+	return d.run([]string{pullrequest.AssignMe}, nil)
+}
+
+// setOptions shows the picker of items, keeping current values the
+// repository no longer offers so picking does not drop them.
+func (d *triageDialog) setOptions(items []pickerItem) {
+	for _, value := range d.current {
+		if !slices.ContainsFunc(items, func(item pickerItem) bool { return item.value == value }) {
+			items = append(items, pickerItem{value: value})
+		}
+	}
+	d.picker = newPicker(items, true, d.current)
+}
+
+func (d *triageDialog) Update(msg tea.Msg) (dialog, tea.Cmd) {
+	switch msg := msg.(type) {
+	case triageOptionsMsg:
+		if msg.url != d.entry.URL {
+			return d, nil
+		}
+		d.err = msg.err
+		if msg.err != nil {
+			return d, nil
+		}
+		d.setOptions(msg.items)
+		return d, textinput.Blink
+	case triagedMsg:
+		d.running = false
+		d.err = msg.err
+		return d, nil
+	case tea.KeyMsg:
+		if d.running {
+			return d, nil
+		}
+		if d.picker == nil {
+			if msg.String() == "esc" || msg.String() == "q" {
+				return nil, nil
+			}
+			return d, nil
+		}
+
+		state, cmd := d.picker.Update(msg)
+		switch state {
+		case pickerCancelled:
+			return nil, nil
+		case pickerDone:
+			picked := d.picker.picked()
+			var add, remove []string
+			for _, value := range picked {
+				if !slices.Contains(d.current, value) {
+					add = append(add, value)
+				}
+			}
+			for _, value := range d.current {
+				if !slices.Contains(picked, value) {
+					remove = append(remove, value)
+				}
+			}
+			if len(add) == 0 && len(remove) == 0 {
+				return nil, nil
+			}
+			d.err = nil
+			d.running = true
+			return d, d.run(add, remove)
+		}
+		return d, cmd
+	}
+
+	// 선택 창의 커서 깜빡임 전달
+	if d.picker != nil {
+		_, cmd := d.picker.Update(msg)
+		return d, cmd
+	}
+	return d, nil
+}
+
+func (d *triageDialog) View() string {
+	lines := []string{
+		infoHeadingStyle.Render(fmt.Sprintf("%s of %s#%d — %s", d.field.name(), d.entry.RepositoryNameWithOwner, d.entry.PrNumber, d.entry.Title)),
+		"",
+	}
+	switch {
+	case d.running:
+		lines = append(lines, infoDimStyle.Render("Updating "+strings.ToLower(d.field.name())+"…"))
+	case d.picker != nil:
+		lines = append(lines, d.picker.View())
+	case d.err == nil:
+		lines = append(lines, infoDimStyle.Render("Loading "+strings.ToLower(d.field.name())+"…"))
+	}
+	if d.err != nil {
+		lines = append(lines, "", checkStyles[model.CheckFailing].Render("⚠ "+errorDetail(d.err)))
+		if d.picker == nil {
+			lines = append(lines, infoDimStyle.Render("esc close"))
+		}
+	}
+	return dialogStyle.Render(strings.Join(lines, "\n"))
+}
+
+// triaged reports a label or assignee change and reloads the pull request.
+func (m *ListModel) triaged(msg triagedMsg) tea.Cmd {
+	ref := fmt.Sprintf("%s#%d", msg.entry.RepositoryNameWithOwner, msg.entry.PrNumber)
+	switch {
+	case msg.field == triageAssignees && slices.Equal(msg.add, []string{pullrequest.AssignMe}) && len(msg.remove) == 0:
+		m.notice = "✓ Assigned you to " + ref
+	default:
+		var changes []string
+		for _, value := range msg.add {
+			changes = append(changes, "+"+value)
+		}
+		for _, value := range msg.remove {
+			changes = append(changes, "−"+value)
+		}
+		m.notice = fmt.Sprintf("✓ Updated the %s of %s: %s", strings.ToLower(msg.field.name()), ref, strings.Join(changes, " "))
+	}
+	return m.reloadEntryCmd(msg.entry)
+}
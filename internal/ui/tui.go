@@ -2,14 +2,19 @@ package ui
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"net"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jinwoo1225/gh-rr/internal/cache"
+	"github.com/jinwoo1225/gh-rr/internal/category"
 	"github.com/jinwoo1225/gh-rr/internal/model"
 	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
 	"github.com/jinwoo1225/gh-rr/internal/utils"
@@ -30,20 +35,167 @@ var (
 				Border(lipgloss.NormalBorder(), false, false, true, false). // 하단 테두리 추가
 				BorderForeground(lipgloss.Color("205"))                     // 테두리 색상
 
+	failedTabStyle = tabStyle.
+			Foreground(lipgloss.Color("196"))
+
+	offlineBadgeStyle = lipgloss.NewStyle().
+				Padding(0, 1).
+				Bold(true).
+				Foreground(lipgloss.Color("230")).
+				Background(lipgloss.Color("196"))
+
+	staleBadgeStyle = offlineBadgeStyle.
+			Background(lipgloss.Color("240"))
+
+	statusStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240"))
+
+	noticeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("42"))
+
+	errorBannerStyle = lipgloss.NewStyle().
+				Padding(0, 1).
+				Foreground(lipgloss.Color("196")).
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(lipgloss.Color("196"))
 )
 
 type ListModel struct {
-	Categories    []string
+	Categories    []category.Category
 	CategoryIndex int
 	Entries       [][]Entry
 	List          list.Model
-	clone         bool
 	quit          bool
 	nextRefresh   time.Time
+	// showInfo replaces the list with the details of the selected entry.
+	showInfo bool
+	// info scrolls the details shown with showInfo; infoURL is the entry they belong to.
+	info    viewport.Model
+	infoURL string
+	// details caches the lazily loaded details of pull requests by URL.
+	details map[string]*detailState
+	// width is the terminal width; wide terminals show the details next to the list.
+	width int
+	// diff replaces the list with the diff of the selected entry while it is open.
+	diff *diffModel
+	// timeline replaces the list with the conversation on the selected entry while it is open.
+	timeline *timelineModel
+	// dialog replaces the list while an action on the selected entry is confirmed.
+	dialog dialog
+	// notice reports the outcome of the last action until the next key press.
+	notice string
+
+	// tabs holds the loading state of each category, indexed like Categories.
+	tabs    []tabState
+	spinner spinner.Model
+	// spinning is set while a spinner tick is scheduled.
+	spinning bool
+	// cache persists the last successful result of every category.
+	cache *cache.Store
+	// fetcher searches the pull requests of every category.
+	fetcher pullrequest.Fetcher
+	// checkouter checks pull requests out; checking out is disabled without one.
+	checkouter Checkouter
+
+	// ctx is the parent of every fetch; it is cancelled when the program exits.
+	ctx context.Context
+	// refreshCtx belongs to the latest refresh and is cancelled when a new one replaces it.
+	refreshCtx    context.Context
+	cancelRefresh context.CancelFunc
+	// generation identifies the latest refresh so results of replaced ones are dropped.
+	generation int
 }
 
-type refreshedMsg struct {
-	entries [][]Entry
+// tabState tracks the loading state of a single category.
+type tabState struct {
+	loading bool
+	// loaded is set once entries are available, either fetched or from the cache.
+	loaded bool
+	// stale is set while the entries come from the cache rather than this session.
+	stale bool
+	// fetchedAt is when the shown entries were fetched.
+	fetchedAt time.Time
+	err       error
+
+	// pullRequests are every loaded pull request, kept for the cache.
+	pullRequests []*model.GithubPullRequest
+	// totalCount is the number of matching pull requests, loaded or not.
+	totalCount int
+	// pages is the number of pages loaded; a refresh reloads as many.
+	pages       int
+	hasNextPage bool
+	// endCursor is where the next page starts.
+	endCursor   string
+	loadingMore bool
+	// moreErr is the failure of the last attempt to load the next page.
+	moreErr error
+}
+
+// NewListModel returns a ListModel whose fetches are bound to ctx.
+// Categories found in store are shown right away as stale; nothing is fetched
+// until the program starts, and every category then loads independently.
+func NewListModel(ctx context.Context, categories []category.Category, l list.Model, store *cache.Store, fetcher pullrequest.Fetcher) *ListModel {
+	s := spinner.New(spinner.WithSpinner(spinner.Dot))
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	m := &ListModel{
+		Categories: categories,
+		Entries:    make([][]Entry, len(categories)),
+		List:       l,
+		tabs:       make([]tabState, len(categories)),
+		details:    map[string]*detailState{},
+		spinner:    s,
+		cache:      store,
+		fetcher:    fetcher,
+		ctx:        ctx,
+		refreshCtx: ctx,
+	}
+
+	now := time.Now()
+	for i, c := range categories {
+		cached, ok := store.Get(c.Key())
+		if !ok {
+			continue
+		}
+		m.Entries[i] = BuildEntries(cached.PullRequests, now)
+		m.tabs[i] = tabState{
+			loaded:       true,
+			stale:        true,
+			fetchedAt:    cached.FetchedAt,
+			pullRequests: cached.PullRequests,
+			totalCount:   max(cached.TotalCount, len(cached.PullRequests)),
+			pages:        max(cached.Pages, 1),
+			hasNextPage:  cached.HasNextPage,
+			endCursor:    cached.EndCursor,
+		}
+	}
+	m.List.SetItems(ItemsFromEntries(m.Entries[m.CategoryIndex]))
+
+	return m
+}
+
+// loadMoreThreshold is how close to the end of the list the cursor gets
+// before the next page is loaded.
+const loadMoreThreshold = 5
+
+// categoryLoadedMsg carries the result of fetching a single category.
+type categoryLoadedMsg struct {
+	generation int
+	index      int
+	result     category.Result
+}
+
+// categoriesLoadedMsg carries the result of fetching every category at once.
+type categoriesLoadedMsg struct {
+	generation int
+	results    []category.Result
+}
+
+// categoryPageMsg carries the next page of an already loaded category.
+type categoryPageMsg struct {
+	generation int
+	index      int
+	result     category.Result
 }
 
 // tickMsg signals the passing of time for auto-refresh countdown.
@@ -56,78 +208,266 @@ func tickCmd() tea.Cmd {
 	})
 }
 
+// startRefresh cancels the refresh in flight, if any, and starts a new generation.
+func (m *ListModel) startRefresh() {
+	m.stopRefresh()
+	m.refreshCtx, m.cancelRefresh = context.WithCancel(m.ctx)
+	m.generation++
+}
+
+// stopRefresh cancels the refresh in flight, killing its gh processes.
+func (m *ListModel) stopRefresh() {
+	if m.cancelRefresh != nil {
+		m.cancelRefresh()
+		m.cancelRefresh = nil
+	}
+}
+
+// refreshCmd reloads every category. A BatchFetcher reloads the first page of
+// all of them in one request; otherwise each one reports back as soon as it finishes.
 func (m *ListModel) refreshCmd() tea.Cmd {
+	m.startRefresh()
+	m.forgetFailedDetails()
+	if _, ok := m.fetcher.(pullrequest.BatchFetcher); ok {
+		return tea.Batch(m.loadAllCmd(), m.spinCmd())
+	}
+
+	cmds := make([]tea.Cmd, 0, len(m.Categories)+1)
+	for i := range m.Categories {
+		cmds = append(cmds, m.loadCmd(i))
+	}
+	cmds = append(cmds, m.spinCmd())
+	return tea.Batch(cmds...)
+}
+
+// loadAllCmd marks every category as loading and fetches their first pages
+// together, then reloads the further pages that are currently shown.
+func (m *ListModel) loadAllCmd() tea.Cmd {
+	pages := make([]int, len(m.tabs))
+	for i := range m.tabs {
+		m.tabs[i].loading = true
+		pages[i] = m.tabs[i].pages
+	}
+	ctx, generation, fetcher, categories := m.refreshCtx, m.generation, m.fetcher, m.Categories
 	return func() tea.Msg {
-		ctx := context.Background()
-		var (
-			reviewRequested []*model.GithubPullRequest
-			myPullRequests  []*model.GithubPullRequest
-			draftPulls      []*model.GithubPullRequest
-			involvedPulls   []*model.GithubPullRequest
-			err             error
-			wg              sync.WaitGroup
-		)
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			reviewRequested, err = pullrequest.Fetch(ctx,
-				pullrequest.StateOpen, pullrequest.ReviewRequestedMe,
-				pullrequest.DraftFalse, pullrequest.ArchivedFalse,
-				pullrequest.SortCreated)
-			if err != nil {
-				log.Println("refresh: review requested error:", err)
-			}
-		}()
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			myPullRequests, err = pullrequest.Fetch(ctx,
-				pullrequest.StateOpen, pullrequest.AuthorMe,
-				pullrequest.DraftFalse, pullrequest.ArchivedFalse,
-				pullrequest.SortCreated)
-			if err != nil {
-				log.Println("refresh: my pull requests error:", err)
+		results := category.FetchAll(ctx, fetcher, categories, category.DefaultTimeout)
+		for i, result := range results {
+			if result.Err == nil && result.Pages < pages[i] {
+				results[i] = category.FetchRest(ctx, fetcher, categories[i], result, pages[i], category.DefaultTimeout)
 			}
-		}()
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			draftPulls, err = pullrequest.Fetch(ctx,
-				pullrequest.StateOpen, pullrequest.DraftTrue,
-				pullrequest.InvolvesMe, pullrequest.ArchivedFalse,
-				pullrequest.SortCreated)
-			if err != nil {
-				log.Println("refresh: draft pull requests error:", err)
-			}
-		}()
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			involvedPulls, err = pullrequest.Fetch(ctx,
-				pullrequest.StateOpen, pullrequest.InvolvesMe,
-				pullrequest.DraftFalse, pullrequest.ArchivedFalse,
-				pullrequest.SortCreated)
-			if err != nil {
-				log.Println("refresh: involved pull requests error:", err)
+		}
+		return categoriesLoadedMsg{generation: generation, results: results}
+	}
+}
+
+// loadCmd marks a category as loading and fetches it within the current refresh,
+// reloading as many pages as are currently shown.
+func (m *ListModel) loadCmd(index int) tea.Cmd {
+	m.tabs[index].loading = true
+	ctx, generation, fetcher := m.refreshCtx, m.generation, m.fetcher
+	c, pages := m.Categories[index], m.tabs[index].pages
+	return func() tea.Msg {
+		return categoryLoadedMsg{
+			generation: generation,
+			index:      index,
+			result:     category.FetchOne(ctx, fetcher, c, pages, category.DefaultTimeout),
+		}
+	}
+}
+
+// loadMoreCmd fetches the page following the loaded ones within the current refresh.
+func (m *ListModel) loadMoreCmd(index int) tea.Cmd {
+	m.tabs[index].loadingMore = true
+	m.tabs[index].moreErr = nil
+	ctx, generation, fetcher := m.refreshCtx, m.generation, m.fetcher
+	c, cursor := m.Categories[index], m.tabs[index].endCursor
+	return func() tea.Msg {
+		return categoryPageMsg{
+			generation: generation,
+			index:      index,
+			result:     category.FetchPage(ctx, fetcher, c, cursor, category.DefaultTimeout),
+		}
+	}
+}
+
+// maybeLoadMoreCmd loads the next page once the cursor nears the end of the list.
+func (m *ListModel) maybeLoadMoreCmd() tea.Cmd {
+	tab := m.tabs[m.CategoryIndex]
+	if !tab.hasNextPage || tab.loading || tab.loadingMore || tab.moreErr != nil {
+		return nil
+	}
+	if m.List.FilterState() != list.Unfiltered {
+		return nil
+	}
+	if m.List.Index() < len(m.Entries[m.CategoryIndex])-loadMoreThreshold {
+		return nil
+	}
+	return tea.Batch(m.loadMoreCmd(m.CategoryIndex), m.spinCmd())
+}
+
+// spinCmd starts the spinner unless it is already running.
+func (m *ListModel) spinCmd() tea.Cmd {
+	if m.spinning {
+		return nil
+	}
+	m.spinning = true
+	return m.spinner.Tick
+}
+
+func (m *ListModel) isLoading() bool {
+	if m.diff != nil && m.diff.loading || m.timeline != nil && m.timeline.loading {
+		return true
+	}
+	for _, tab := range m.tabs {
+		if tab.loading || tab.loadingMore {
+			return true
+		}
+	}
+	return false
+}
+
+// setResult stores the result of a category. A failed fetch keeps the
+// previously loaded entries so a transient error does not empty the tab.
+func (m *ListModel) setResult(index int, result category.Result, now time.Time) {
+	if errors.Is(result.Err, context.Canceled) {
+		return
+	}
+	tab := &m.tabs[index]
+	tab.loading = false
+	tab.err = result.Err
+	if result.Err == nil {
+		tab.loaded = true
+		tab.stale = false
+		tab.fetchedAt = now
+		tab.pullRequests = result.PullRequests
+		tab.totalCount = result.TotalCount
+		tab.pages = result.Pages
+		tab.hasNextPage = result.HasNextPage
+		tab.endCursor = result.EndCursor
+		tab.moreErr = nil
+		m.Entries[index] = BuildEntries(tab.pullRequests, now)
+		m.saveCache(index)
+	}
+	if index == m.CategoryIndex {
+		selected, ok := m.SelectedEntry()
+		m.List.SetItems(ItemsFromEntries(m.Entries[m.CategoryIndex]))
+		// 새로고침 후에도 선택한 PR에 커서를 유지
+		if ok {
+			m.selectURL(selected.URL)
+		}
+	}
+}
+
+// selectURL moves the cursor to the pull request at url when it is listed.
+func (m *ListModel) selectURL(url string) {
+	for i, entry := range m.Entries[m.CategoryIndex] {
+		if entry.URL == url {
+			m.List.Select(i)
+			return
+		}
+	}
+}
+
+// appendPage adds the next page of a category below the loaded entries,
+// skipping pull requests that moved onto it since the previous page was loaded.
+func (m *ListModel) appendPage(index int, result category.Result, now time.Time) {
+	if errors.Is(result.Err, context.Canceled) {
+		return
+	}
+	tab := &m.tabs[index]
+	tab.loadingMore = false
+	tab.moreErr = result.Err
+	if result.Err != nil {
+		return
+	}
+
+	seen := make(map[string]bool, len(tab.pullRequests))
+	for _, pullRequest := range tab.pullRequests {
+		seen[pullRequest.URL] = true
+	}
+	for _, pullRequest := range result.PullRequests {
+		if !seen[pullRequest.URL] {
+			tab.pullRequests = append(tab.pullRequests, pullRequest)
+		}
+	}
+	tab.totalCount = result.TotalCount
+	tab.pages += result.Pages
+	tab.hasNextPage = result.HasNextPage
+	tab.endCursor = result.EndCursor
+	m.Entries[index] = BuildEntries(tab.pullRequests, now)
+	m.saveCache(index)
+
+	if index == m.CategoryIndex {
+		m.List.SetItems(ItemsFromEntries(m.Entries[m.CategoryIndex]))
+	}
+}
+
+// updatePullRequest replaces every listed copy of pullRequest with it. The
+// body is left out as it is only kept with the details.
+func (m *ListModel) updatePullRequest(pullRequest *model.GithubPullRequest) {
+	updated := *pullRequest
+	updated.Body = ""
+	now := time.Now()
+	for i := range m.tabs {
+		tab := &m.tabs[i]
+		changed := false
+		for j, listed := range tab.pullRequests {
+			if listed.URL == updated.URL {
+				tab.pullRequests[j] = &updated
+				changed = true
 			}
-		}()
-		wg.Wait()
+		}
+		if !changed {
+			continue
+		}
+		m.Entries[i] = BuildEntries(tab.pullRequests, now)
+		m.saveCache(i)
+		if i == m.CategoryIndex {
+			m.List.SetItems(ItemsFromEntries(m.Entries[i]))
+		}
+	}
+}
 
-		now := time.Now()
-		return refreshedMsg{
-			entries: [][]Entry{
-				BuildEntries(reviewRequested, now),
-				BuildEntries(myPullRequests, now),
-				BuildEntries(draftPulls, now),
-				BuildEntries(involvedPulls, now),
-			},
+// removePullRequest drops the pull request at url from a category, e.g. once
+// it no longer matches after a change.
+func (m *ListModel) removePullRequest(index int, url string) {
+	tab := &m.tabs[index]
+	pullRequests := make([]*model.GithubPullRequest, 0, len(tab.pullRequests))
+	for _, pullRequest := range tab.pullRequests {
+		if pullRequest.URL != url {
+			pullRequests = append(pullRequests, pullRequest)
 		}
 	}
+	if len(pullRequests) == len(tab.pullRequests) {
+		return
+	}
+	tab.pullRequests = pullRequests
+	tab.totalCount--
+	m.Entries[index] = BuildEntries(tab.pullRequests, time.Now())
+	m.saveCache(index)
+	if index == m.CategoryIndex {
+		m.List.SetItems(ItemsFromEntries(m.Entries[index]))
+	}
+}
+
+// saveCache persists the loaded pull requests of a category.
+func (m *ListModel) saveCache(index int) {
+	tab := m.tabs[index]
+	m.cache.Put(m.Categories[index].Key(), cache.Entry{
+		FetchedAt:    tab.fetchedAt,
+		PullRequests: tab.pullRequests,
+		TotalCount:   tab.totalCount,
+		Pages:        tab.pages,
+		HasNextPage:  tab.hasNextPage,
+		EndCursor:    tab.endCursor,
+	})
+	// A cache that cannot be written only costs the next startup its head start.
+	_ = m.cache.Save()
 }
 
 func (m *ListModel) Init() tea.Cmd {
 	m.nextRefresh = time.Now().Add(time.Minute)
-	return tickCmd()
+	return tea.Batch(m.refreshCmd(), tickCmd())
 }
 
 func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -139,11 +479,163 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(m.refreshCmd(), tickCmd())
 		}
 		return m, tickCmd()
-	case refreshedMsg:
-		m.Entries = msg.entries
-		m.List.SetItems(ItemsFromEntries(m.Entries[m.CategoryIndex]))
+	case categoryLoadedMsg:
+		if msg.generation != m.generation {
+			return m, nil
+		}
+		m.setResult(msg.index, msg.result, time.Now())
+		return m, m.detailCmd()
+	case categoriesLoadedMsg:
+		if msg.generation != m.generation {
+			return m, nil
+		}
+		now := time.Now()
+		for i, result := range msg.results {
+			m.setResult(i, result, now)
+		}
+		return m, m.detailCmd()
+	case categoryPageMsg:
+		if msg.generation != m.generation {
+			m.tabs[msg.index].loadingMore = false
+			return m, nil
+		}
+		m.appendPage(msg.index, msg.result, time.Now())
+		return m, m.detailCmd()
+	case detailLoadedMsg:
+		m.setDetail(msg)
+		return m, nil
+	case editorFinishedMsg:
+		if m.dialog != nil {
+			var cmd tea.Cmd
+			m.dialog, cmd = m.dialog.Update(msg)
+			return m, cmd
+		}
 		return m, nil
+	case reviewSubmittedMsg:
+		if msg.err != nil {
+			if m.dialog != nil {
+				m.dialog, _ = m.dialog.Update(msg)
+			}
+			return m, nil
+		}
+		m.dialog = nil
+		return m, tea.Batch(m.reviewed(msg), m.detailCmd())
+	case mergeDoneMsg:
+		if msg.err != nil {
+			if m.dialog != nil {
+				m.dialog, _ = m.dialog.Update(msg)
+			}
+			return m, nil
+		}
+		m.dialog = nil
+		return m, tea.Batch(m.merged(msg), m.detailCmd())
+	case draftChangedMsg:
+		if msg.err != nil {
+			if m.dialog != nil {
+				m.dialog, _ = m.dialog.Update(msg)
+			}
+			return m, nil
+		}
+		m.dialog = nil
+		return m, tea.Batch(m.draftChanged(msg), m.detailCmd())
+	case triagedMsg:
+		if msg.err != nil {
+			if m.dialog != nil {
+				m.dialog, _ = m.dialog.Update(msg)
+			}
+			return m, nil
+		}
+		m.dialog = nil
+		return m, tea.Batch(m.triaged(msg), m.detailCmd())
+	case commentPostedMsg:
+		if msg.err != nil {
+			if m.dialog != nil {
+				m.dialog, _ = m.dialog.Update(msg)
+			}
+			return m, nil
+		}
+		m.dialog = nil
+		return m, m.commented(msg)
+	case reviewersChangedMsg:
+		// 검토자 목록은 변경 후에도 열어 두고 결과를 반영
+		if m.dialog != nil {
+			m.dialog, _ = m.dialog.Update(msg)
+		}
+		if msg.err != nil {
+			return m, nil
+		}
+		return m, m.reviewersChanged(msg)
+	case timelineLoadedMsg:
+		if m.timeline != nil && m.timeline.entry.URL == msg.url {
+			m.timeline.setTimeline(msg.timeline, msg.err)
+		}
+		return m, nil
+	case diffLoadedMsg:
+		if m.diff != nil && m.diff.entry.URL == msg.url {
+			m.diff.setDiff(msg.diff, msg.err)
+		}
+		return m, nil
+	case shellExitedMsg:
+		if msg.err != nil {
+			m.notice = "Shell in " + msg.dir + " failed: " + msg.err.Error()
+		} else {
+			m.notice = "✓ Back from the shell in " + msg.dir
+		}
+		return m, nil
+	case diffThreadsLoadedMsg:
+		if m.diff != nil && m.diff.entry.URL == msg.url {
+			m.diff.setThreads(msg.threads, msg.err)
+		}
+		return m, nil
+	case pendingCommentMsg:
+		m.addPendingComment(msg)
+		return m, nil
+	case spinner.TickMsg:
+		if !m.isLoading() {
+			m.spinning = false
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
 	case tea.KeyMsg:
+		m.notice = ""
+		// 대화 상자가 열려 있으면 대화 상자에서 키 입력 처리
+		if m.dialog != nil && msg.String() != "ctrl+c" {
+			var cmd tea.Cmd
+			m.dialog, cmd = m.dialog.Update(msg)
+			return m, cmd
+		}
+		// diff를 보는 중에는 diff 화면에서 키 입력 처리
+		if m.diff != nil && msg.String() != "ctrl+c" {
+			action, cmd := m.diff.Update(msg)
+			switch action {
+			case diffClose:
+				m.diff = nil
+			case diffComment:
+				return m, m.openLineComment()
+			case diffApprove, diffCommentReview, diffRequestChanges:
+				return m, m.openReview(diffReviewEvents[action])
+			}
+			return m, cmd
+		}
+		// 대화 내용을 보는 중에는 대화 화면에서 키 입력 처리
+		if m.timeline != nil && msg.String() != "ctrl+c" {
+			action, cmd := m.timeline.Update(msg)
+			switch action {
+			case timelineClose:
+				m.timeline = nil
+			case timelineComment:
+				return m, m.openComment(nil)
+			case timelineReply:
+				return m, m.openComment(m.timeline.currentThread())
+			}
+			return m, cmd
+		}
+		// 필터를 입력하는 중에는 단축키 대신 목록에서 처리
+		if m.List.FilterState() == list.Filtering {
+			break
+		}
 		switch msg.String() {
 		case "left":
 			m.CategoryIndex = (m.CategoryIndex + len(m.Categories) - 1) % len(m.Categories)
@@ -152,31 +644,115 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.CategoryIndex = (m.CategoryIndex + 1) % len(m.Categories)
 			m.List.SetItems(ItemsFromEntries(m.Entries[m.CategoryIndex]))
 		case "enter":
-			entry := m.Entries[m.CategoryIndex][m.List.Index()]
+			entry, ok := m.SelectedEntry()
+			if !ok {
+				return m, nil
+			}
 			utils.OpenURL(entry.URL)
-			m.clone = false
 			return m, nil
+		case "i":
+			if _, ok := m.SelectedEntry(); ok {
+				m.showInfo = !m.showInfo
+			}
+			return m, m.detailCmd()
+		case "v":
+			fetcher, ok := m.fetcher.(pullrequest.DiffFetcher)
+			entry, selected := m.SelectedEntry()
+			if !ok || !selected {
+				return m, nil
+			}
+			m.diff = newDiffModel(entry, m.width-docStyle.GetHorizontalFrameSize(), m.List.Height())
+			return m, tea.Batch(m.loadDiffCmd(fetcher, entry), m.loadDiffThreadsCmd(entry), m.spinCmd())
+		case "t":
+			fetcher, ok := m.fetcher.(pullrequest.TimelineFetcher)
+			entry, selected := m.SelectedEntry()
+			if !ok || !selected {
+				return m, nil
+			}
+			m.timeline = newTimelineModel(entry, m.width-docStyle.GetHorizontalFrameSize(), m.List.Height())
+			return m, tea.Batch(m.loadTimelineCmd(fetcher, entry), m.spinCmd())
+		case "a":
+			return m, m.openReview(pullrequest.ReviewApprove)
+		case "m":
+			return m, m.openReview(pullrequest.ReviewComment)
+		case "x":
+			return m, m.openReview(pullrequest.ReviewRequestChanges)
+		case "M":
+			return m, m.openMerge()
+		case "e":
+			return m, m.openReviewers()
+		case "D":
+			return m, m.openDraft()
+		case "L":
+			return m, m.openTriage(triageLabels)
+		case "A":
+			return m, m.openTriage(triageAssignees)
+		case "@":
+			return m, m.assignMe()
+		case "esc":
+			if m.showInfo {
+				m.showInfo = false
+				return m, nil
+			}
 		case "c":
-			m.clone = true
-			return m, tea.Quit
+			return m, m.openCheckout()
+		case "U":
+			return m, m.openRestore()
 		case "r":
 			now := time.Now()
 			m.nextRefresh = now.Add(time.Minute)
 			return m, tea.Batch(m.refreshCmd(), tickCmd())
+		case "R":
+			tab := m.tabs[m.CategoryIndex]
+			switch {
+			case tab.loading || tab.loadingMore:
+				return m, nil
+			case tab.err != nil:
+				return m, tea.Batch(m.loadCmd(m.CategoryIndex), m.spinCmd())
+			case tab.moreErr != nil:
+				return m, tea.Batch(m.loadMoreCmd(m.CategoryIndex), m.spinCmd())
+			}
+			return m, nil
 		case "q", "ctrl+c":
+			m.stopRefresh()
 			m.quit = true
 			return m, tea.Quit
 		}
 	case tea.WindowSizeMsg:
 		h, v := docStyle.GetFrameSize()
-		m.List.SetSize(msg.Width-h, msg.Height-v-3)
+		m.width = msg.Width
+		// 탭, 여백, 상태 표시 줄을 제외한 높이
+		width, height := msg.Width-h, msg.Height-v-4
+		// 넓은 화면에서는 목록 오른쪽에 상세 정보 표시
+		if m.isSplit() {
+			width /= 2
+		}
+		m.List.SetSize(width, height)
+		if m.diff != nil {
+			m.diff.setSize(msg.Width-h, height)
+		}
+		if m.timeline != nil {
+			m.timeline.setSize(msg.Width-h, height)
+		}
+		infoH, infoV := infoStyle.GetFrameSize()
+		m.info.Width, m.info.Height = msg.Width-h-infoH, height-infoV
 	}
 	m.List.Title = ""
 
 	var cmd tea.Cmd
+	// 대화 상자의 커서 깜빡임 등은 대화 상자로 전달
+	if m.dialog != nil {
+		m.dialog, cmd = m.dialog.Update(msg)
+		return m, cmd
+	}
+	// 상세 정보를 전체 화면으로 보는 중에는 키 입력으로 스크롤
+	if _, ok := msg.(tea.KeyMsg); ok && m.showInfo {
+		m.info, cmd = m.info.Update(msg)
+		return m, cmd
+	}
 	m.List, cmd = m.List.Update(msg)
 
-	return m, cmd
+	return m, tea.Batch(cmd, m.maybeLoadMoreCmd(), m.detailCmd())
 }
 
 func (m *ListModel) View() string {
@@ -184,11 +760,15 @@ func (m *ListModel) View() string {
 
 	// 탭 렌더링 개선
 	var tabsView []string
-	for i, cat := range m.Categories {
-		if i == m.CategoryIndex {
-			tabsView = append(tabsView, selectedTabStyle.Render(cat))
-		} else {
-			tabsView = append(tabsView, tabStyle.Render(cat))
+	for i := range m.Categories {
+		label := m.tabLabel(i)
+		switch {
+		case i == m.CategoryIndex:
+			tabsView = append(tabsView, selectedTabStyle.Render(label))
+		case m.tabs[i].err != nil:
+			tabsView = append(tabsView, failedTabStyle.Render(label))
+		default:
+			tabsView = append(tabsView, tabStyle.Render(label))
 		}
 	}
 
@@ -196,26 +776,194 @@ func (m *ListModel) View() string {
 	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabsView...))
 	sb.WriteString("\n\n") // 아래 콘텐츠와의 여백 추가
 
+	// 마지막 작업의 결과 표시
+	if m.notice != "" {
+		sb.WriteString(noticeStyle.Render(m.notice))
+		sb.WriteString("\n")
+	}
+
+	// 선택한 PR에 대한 작업을 확인하는 대화 상자 표시
+	if m.dialog != nil {
+		sb.WriteString(m.dialog.View())
+		return docStyle.Render(sb.String())
+	}
+
+	// 선택한 PR의 diff 표시
+	if m.diff != nil {
+		sb.WriteString(m.diff.View(m.spinner.View()))
+		return docStyle.Render(sb.String())
+	}
+
+	// 선택한 PR의 대화 내용 표시
+	if m.timeline != nil {
+		sb.WriteString(m.timeline.View(m.spinner.View()))
+		return docStyle.Render(sb.String())
+	}
+
+	tab := m.tabs[m.CategoryIndex]
+	entries := m.Entries[m.CategoryIndex]
+
+	// 불러오기에 실패했고 보여줄 목록도 없는 경우 오류 배너 표시
+	if tab.err != nil && !tab.loaded {
+		sb.WriteString(m.errorBanner(tab.err))
+		return docStyle.Render(sb.String())
+	}
+
+	// 캐시된 목록, 실패 이전의 목록, 일부만 불러온 목록인 경우 상태 표시
+	if status := m.statusLine(tab, time.Now()); status != "" {
+		sb.WriteString(status)
+		sb.WriteString("\n")
+	}
+
+	// 처음 불러오는 중인 경우 로딩 메시지 표시
+	if !tab.loaded {
+		loadingMsg := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Render(m.spinner.View() + " Loading " + m.Categories[m.CategoryIndex].Name + "…")
+		sb.WriteString(loadingMsg)
+		return docStyle.Render(sb.String())
+	}
+
+	// 선택한 PR의 상세 정보를 전체 화면으로 표시
+	entry, selected := m.SelectedEntry()
+	if selected && m.showInfo {
+		sb.WriteString(m.infoView(entry))
+		return docStyle.Render(sb.String())
+	}
+
 	// 목록이 비어있는 경우 메시지 표시
-	if len(m.Entries[m.CategoryIndex]) == 0 {
+	switch {
+	case len(entries) == 0:
 		emptyMsg := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")).
 			Italic(true).
 			Render("🥳 Nothing to see here 🎊")
 		sb.WriteString(emptyMsg)
-	} else {
+	case selected && m.isSplit():
+		// 목록과 상세 정보를 나란히 표시
+		listView := lipgloss.NewStyle().Width(m.List.Width()).Render(m.List.View())
+		detailWidth := m.width - docStyle.GetHorizontalFrameSize() - m.List.Width() - 1
+		sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, listView, " ", m.detailView(entry, detailWidth, m.List.Height())))
+	default:
 		sb.WriteString(m.List.View())
 	}
 
 	return docStyle.Render(sb.String())
 }
 
-func (m *ListModel) IsQuit() bool {
-	return m.quit
+// tabLabel renders a category name with its loading, loaded or failed state.
+func (m *ListModel) tabLabel(index int) string {
+	name := m.Categories[index].Name
+	tab := m.tabs[index]
+	switch {
+	case tab.loading:
+		return m.spinner.View() + " " + name
+	case tab.err != nil:
+		return "⚠ " + name
+	case tab.loaded && len(tab.pullRequests) < tab.totalCount:
+		return fmt.Sprintf("%s (%d/%d)", name, len(tab.pullRequests), tab.totalCount)
+	case tab.loaded:
+		return fmt.Sprintf("%s (%d)", name, len(m.Entries[index]))
+	default:
+		return name
+	}
+}
+
+// statusLine tells how old the shown entries are, whether refreshing them
+// failed and how many of the matching pull requests are loaded.
+func (m *ListModel) statusLine(tab tabState, now time.Time) string {
+	var parts []string
+	age := utils.HumanizeDuration(int(now.Sub(tab.fetchedAt).Seconds()))
+	switch {
+	case tab.err != nil && isOffline(tab.err):
+		detail, _, _ := strings.Cut(errorDetail(tab.err), "\n")
+		parts = append(parts, offlineBadgeStyle.Render("OFFLINE")+
+			statusStyle.Render(" last updated "+age+" ago · "+detail+" · press R to retry"))
+	case tab.err != nil:
+		detail, _, _ := strings.Cut(errorDetail(tab.err), "\n")
+		parts = append(parts, staleBadgeStyle.Render("CACHED")+
+			statusStyle.Render(" last updated "+age+" ago · refresh failed: "+detail+" · press R to retry"))
+	case tab.stale:
+		status := " last updated " + age + " ago"
+		if tab.loading {
+			status += ", refreshing…"
+		}
+		parts = append(parts, staleBadgeStyle.Render("CACHED")+statusStyle.Render(status))
+	}
+
+	if loaded := len(tab.pullRequests); loaded < tab.totalCount {
+		progress := fmt.Sprintf("showing %d of %d", loaded, tab.totalCount)
+		switch {
+		case tab.loadingMore:
+			progress += " · " + m.spinner.View() + " loading more…"
+		case tab.moreErr != nil:
+			progress += " · failed to load more, press R to retry"
+		case tab.hasNextPage:
+			progress += " · scroll down to load more"
+		default:
+			progress += " · GitHub search returns at most 1000 results"
+		}
+		parts = append(parts, statusStyle.Render(progress))
+	}
+
+	return strings.Join(parts, statusStyle.Render(" · "))
+}
+
+// offlineMessages are what gh prints when it cannot reach GitHub.
+var offlineMessages = []string{
+	"could not connect",
+	"error connecting to",
+	"no such host",
+	"connection refused",
+	"network is unreachable",
+	"i/o timeout",
+}
+
+// isOffline reports whether err means GitHub could not be reached, as opposed
+// to GitHub rejecting the request.
+func isOffline(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	detail := strings.ToLower(errorDetail(err) + "\n" + err.Error())
+	for _, message := range offlineMessages {
+		if strings.Contains(detail, message) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorDetail returns the gh stderr output of err when there is one.
+func errorDetail(err error) string {
+	var fetchErr *pullrequest.FetchError
+	if errors.As(err, &fetchErr) && fetchErr.Stderr != "" {
+		return fetchErr.Stderr
+	}
+	var actionErr *pullrequest.ActionError
+	if errors.As(err, &actionErr) && actionErr.Stderr != "" {
+		return actionErr.Stderr
+	}
+	return err.Error()
 }
 
-func (m *ListModel) IsClone() bool {
-	return m.clone
+// errorBanner renders the failure of the current category, preferring the gh stderr output.
+func (m *ListModel) errorBanner(err error) string {
+	detail := errorDetail(err)
+	title := lipgloss.NewStyle().Bold(true).Render("⚠ Failed to load " + m.Categories[m.CategoryIndex].Name)
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("press R to retry")
+	return errorBannerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, "", detail, "", hint))
+}
+
+// SelectedEntry returns the highlighted entry, if any.
+func (m *ListModel) SelectedEntry() (Entry, bool) {
+	item, ok := m.List.SelectedItem().(itemEntry)
+	return item.entry, ok
+}
+
+func (m *ListModel) IsQuit() bool {
+	return m.quit
 }
 
 // NextRefresh returns the scheduled time for the next automatic refresh.
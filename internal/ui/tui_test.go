@@ -0,0 +1,1127 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/jinwoo1225/gh-rr/internal/cache"
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
+	"github.com/jinwoo1225/gh-rr/internal/pullrequest/fake"
+)
+
+// Indexes of the built-in categories.
+const (
+	reviewRequests = iota
+	myPRs
+	draftPRs
+	involvedPRs
+)
+
+func loadFixtures(t *testing.T) *fake.Fetcher {
+	t.Helper()
+	fetcher, err := fake.Load(filepath.Join("testdata", "fixtures.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fetcher
+}
+
+func newTestModel(t *testing.T, fetcher pullrequest.Fetcher) *ListModel {
+	t.Helper()
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	m := NewListModel(context.Background(), category.Registry(), l, cache.New(""), fetcher)
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	return m
+}
+
+// timers holds the code of the commands that only wait to send a timer
+// message: those of tea.Tick, behind the refresh countdown and the spinner,
+// and the cursor blink of text inputs.
+var timers = func() map[uintptr]bool {
+	blink := cursor.New()
+	return map[uintptr]bool{
+		reflect.ValueOf(tea.Tick(0, nil)).Pointer(): true,
+		reflect.ValueOf(blink.BlinkCmd()).Pointer(): true,
+	}
+}()
+
+// run executes cmd and returns the messages it produces, flattening batches.
+// Timers are skipped rather than waited for; every other command runs to
+// completion, so a command that hangs fails the test with a timeout.
+func run(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil || timers[reflect.ValueOf(cmd).Pointer()] {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, run(c)...)
+		}
+		return msgs
+	}
+	if msg == nil {
+		return nil
+	}
+	return []tea.Msg{msg}
+}
+
+// send delivers msg to m and feeds every resulting message back the way the
+// Bubble Tea runtime would, ignoring timer ticks.
+func send(m *ListModel, msg tea.Msg) {
+	_, cmd := m.Update(msg)
+	for _, next := range run(cmd) {
+		switch next.(type) {
+		case spinner.TickMsg, tickMsg:
+			continue
+		}
+		send(m, next)
+	}
+}
+
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestRefreshLoadsEveryCategory(t *testing.T) {
+	fetcher := loadFixtures(t)
+	m := newTestModel(t, fetcher)
+
+	send(m, keyRunes("r"))
+
+	if got := len(m.Entries[reviewRequests]); got != 2 {
+		t.Errorf("len(Entries[reviewRequests]) = %d; want 2", got)
+	}
+	if got := len(m.Entries[involvedPRs]); got != 1 {
+		t.Errorf("len(Entries[involvedPRs]) = %d; want 1", got)
+	}
+	if m.isLoading() {
+		t.Error("isLoading() = true after every category reported back")
+	}
+	for _, c := range m.Categories {
+		if got := fetcher.Calls(c.Search()); got != 1 {
+			t.Errorf("%s searched %d times; want 1", c.Name, got)
+		}
+	}
+
+	view := m.View()
+	for _, want := range []string{"Review Requests (2)", "⚠ My PRs", "Draft PRs (0)", "Add widget API"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() does not contain %q", want)
+		}
+	}
+}
+
+func TestCategorySwitching(t *testing.T) {
+	m := newTestModel(t, loadFixtures(t))
+	send(m, keyRunes("r"))
+
+	send(m, tea.KeyMsg{Type: tea.KeyLeft})
+	if m.CategoryIndex != involvedPRs {
+		t.Fatalf("CategoryIndex after left = %d; want %d", m.CategoryIndex, involvedPRs)
+	}
+	if entry, ok := m.SelectedEntry(); !ok || entry.Title != "Rotate deploy keys" {
+		t.Errorf("SelectedEntry() = %+v, %v; want Rotate deploy keys", entry, ok)
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyRight})
+	if m.CategoryIndex != reviewRequests {
+		t.Fatalf("CategoryIndex after right = %d; want %d", m.CategoryIndex, reviewRequests)
+	}
+	if got := len(m.List.Items()); got != 2 {
+		t.Errorf("len(List.Items()) = %d; want 2", got)
+	}
+}
+
+func TestEmptyAndFailedCategoriesLookDifferent(t *testing.T) {
+	m := newTestModel(t, loadFixtures(t))
+	send(m, keyRunes("r"))
+
+	m.CategoryIndex = draftPRs
+	empty := m.View()
+	if !strings.Contains(empty, "Nothing to see here") {
+		t.Errorf("empty category View() = %q; want the empty message", empty)
+	}
+
+	m.CategoryIndex = myPRs
+	failed := m.View()
+	if strings.Contains(failed, "Nothing to see here") {
+		t.Error("failed category View() shows the empty message")
+	}
+	for _, want := range []string{"Failed to load My PRs", "HTTP 401: Bad credentials", "press R to retry"} {
+		if !strings.Contains(failed, want) {
+			t.Errorf("failed category View() does not contain %q", want)
+		}
+	}
+}
+
+func TestRetryRecoversFailedCategory(t *testing.T) {
+	fetcher := loadFixtures(t)
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+	send(m, tea.KeyMsg{Type: tea.KeyRight})
+
+	query := m.Categories[myPRs].Search().String()
+	fetcher.Set(fake.Fixture{
+		Query: query,
+		PullRequests: []*model.GithubPullRequest{
+			{PrNumber: 1, RepositoryNameWithOwner: "acme/widgets", Title: "Mine", URL: "https://github.com/acme/widgets/pull/1"},
+		},
+	})
+	send(m, keyRunes("R"))
+
+	if err := m.tabs[myPRs].err; err != nil {
+		t.Fatalf("error after retry = %v", err)
+	}
+	if entry, ok := m.SelectedEntry(); !ok || entry.Title != "Mine" {
+		t.Errorf("SelectedEntry() = %+v, %v; want Mine", entry, ok)
+	}
+	if got := fetcher.Calls(m.Categories[reviewRequests].Search()); got != 1 {
+		t.Errorf("retry refetched Review Requests; calls = %d, want 1", got)
+	}
+}
+
+func TestStatusLineOfflineOnlyForNetworkErrors(t *testing.T) {
+	m := newTestModel(t, loadFixtures(t))
+	now := time.Now()
+	for _, tc := range []struct {
+		name    string
+		err     error
+		offline bool
+	}{
+		{"timeout", fmt.Errorf("fetching: %w", context.DeadlineExceeded), true},
+		{"dns", &net.DNSError{Err: "no such host", Name: "api.github.com", IsNotFound: true}, true},
+		{"gh offline", &pullrequest.FetchError{Err: errors.New("exit status 1"), Stderr: "error connecting to api.github.com"}, true},
+		{"auth", &pullrequest.FetchError{Err: errors.New("exit status 1"), Stderr: "HTTP 401: Bad credentials"}, false},
+		{"query", errors.New("GraphQL: Field 'nope' doesn't exist on type 'PullRequest'"), false},
+	} {
+		status := m.statusLine(tabState{loaded: true, stale: true, fetchedAt: now, err: tc.err}, now)
+		if got := strings.Contains(status, "OFFLINE"); got != tc.offline {
+			t.Errorf("%s: statusLine() = %q; OFFLINE shown = %v, want %v", tc.name, status, got, tc.offline)
+		}
+		if !tc.offline && !strings.Contains(status, "CACHED") {
+			t.Errorf("%s: statusLine() = %q; want the CACHED marker", tc.name, status)
+		}
+		if detail, _, _ := strings.Cut(errorDetail(tc.err), "\n"); !strings.Contains(status, detail) {
+			t.Errorf("%s: statusLine() = %q; want the error %q", tc.name, status, detail)
+		}
+	}
+}
+
+func TestReplacedRefreshIsDropped(t *testing.T) {
+	m := newTestModel(t, loadFixtures(t))
+	send(m, keyRunes("r"))
+
+	stale := categoryLoadedMsg{
+		generation: m.generation - 1,
+		index:      reviewRequests,
+		result:     category.Result{},
+	}
+	send(m, stale)
+
+	if got := len(m.Entries[reviewRequests]); got != 2 {
+		t.Errorf("len(Entries[reviewRequests]) = %d after a stale result; want 2", got)
+	}
+}
+
+func TestScrollingLoadsNextPage(t *testing.T) {
+	pullRequests := make([]*model.GithubPullRequest, 45)
+	for i := range pullRequests {
+		pullRequests[i] = &model.GithubPullRequest{
+			PrNumber:                i + 1,
+			RepositoryNameWithOwner: "acme/platform",
+			Title:                   fmt.Sprintf("Change %d", i+1),
+			URL:                     fmt.Sprintf("https://github.com/acme/platform/pull/%d", i+1),
+		}
+	}
+	fetcher := fake.New(fake.Fixture{
+		Query:        category.Registry()[reviewRequests].Search().String(),
+		PullRequests: pullRequests,
+	})
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	if got := len(m.Entries[reviewRequests]); got != pullrequest.DefaultPerPage {
+		t.Fatalf("len(Entries) = %d; want one page of %d", got, pullrequest.DefaultPerPage)
+	}
+	if !strings.Contains(m.View(), "Review Requests (30/45)") {
+		t.Error("View() does not show the total match count")
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyEnd})
+	if got := len(m.Entries[reviewRequests]); got != 45 {
+		t.Errorf("len(Entries) after scrolling = %d; want 45", got)
+	}
+}
+
+// batchFetcher serves SearchAll from a fake.Fetcher one query at a time.
+type batchFetcher struct {
+	*fake.Fetcher
+}
+
+func (f batchFetcher) SearchAll(ctx context.Context, queries []pullrequest.Query) ([]*pullrequest.Page, []error) {
+	pages := make([]*pullrequest.Page, len(queries))
+	errs := make([]error, len(queries))
+	for i, q := range queries {
+		pages[i], errs[i] = f.Search(ctx, q, "")
+	}
+	return pages, errs
+}
+
+func TestRefreshKeepsLoadedPages(t *testing.T) {
+	pullRequests := make([]*model.GithubPullRequest, 45)
+	for i := range pullRequests {
+		pullRequests[i] = &model.GithubPullRequest{
+			PrNumber:                i + 1,
+			RepositoryNameWithOwner: "acme/platform",
+			Title:                   fmt.Sprintf("Change %d", i+1),
+			URL:                     fmt.Sprintf("https://github.com/acme/platform/pull/%d", i+1),
+		}
+	}
+	fixture := fake.Fixture{
+		Query:        category.Registry()[reviewRequests].Search().String(),
+		PullRequests: pullRequests,
+	}
+	for name, fetcher := range map[string]pullrequest.Fetcher{
+		"single": fake.New(fixture),
+		"batch":  batchFetcher{fake.New(fixture)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			m := newTestModel(t, fetcher)
+			send(m, keyRunes("r"))
+			send(m, tea.KeyMsg{Type: tea.KeyEnd})
+			if got := len(m.Entries[reviewRequests]); got != 45 {
+				t.Fatalf("len(Entries) after scrolling = %d; want 45", got)
+			}
+			m.List.Select(40)
+
+			send(m, keyRunes("r"))
+			if got := len(m.Entries[reviewRequests]); got != 45 {
+				t.Errorf("len(Entries) after refreshing = %d; want the 45 loaded before", got)
+			}
+			if entry, _ := m.SelectedEntry(); entry.PrNumber != 41 {
+				t.Errorf("selected #%d after refreshing; want #41", entry.PrNumber)
+			}
+		})
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	m := newTestModel(t, loadFixtures(t))
+	send(m, keyRunes("r"))
+
+	view := m.View()
+	for _, want := range []string{"✗ acme/widgets — Add widget API", "✓ acme/gadgets — Bump lodash"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() does not contain %q", want)
+		}
+	}
+
+	send(m, keyRunes("i"))
+	info := m.View()
+	for _, want := range []string{"Checks:", "failing", "test (ubuntu-latest)", "lint"} {
+		if !strings.Contains(info, want) {
+			t.Errorf("info View() does not contain %q", want)
+		}
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showInfo {
+		t.Error("esc did not close the details")
+	}
+}
+
+func TestPartialCheckStatus(t *testing.T) {
+	entry := BuildEntries([]*model.GithubPullRequest{{Partial: true}}, time.Now())[0]
+	if checks := strings.Join(renderChecks(entry), "\n"); !strings.Contains(checks, "unknown") || strings.Contains(checks, "none") {
+		t.Errorf("renderChecks() = %q; want unknown", checks)
+	}
+}
+
+func TestReviewState(t *testing.T) {
+	m := newTestModel(t, loadFixtures(t))
+	send(m, keyRunes("r"))
+
+	if !strings.Contains(m.View(), "Review: ✗ changes requested") {
+		t.Error("View() does not show the review decision")
+	}
+
+	send(m, keyRunes("i"))
+	info := m.View()
+	for _, want := range []string{"monalisa  ✗ changes requested", "hubot  💬 commented, ● review requested", "acme/platform  ● review requested"} {
+		if !strings.Contains(info, want) {
+			t.Errorf("info View() does not contain %q", want)
+		}
+	}
+}
+
+func TestPartialReviewState(t *testing.T) {
+	entry := BuildEntries([]*model.GithubPullRequest{{Partial: true}}, time.Now())[0]
+	if reviewers := strings.Join(renderReviewers(entry), "\n"); !strings.Contains(reviewers, "unknown") || strings.Contains(reviewers, "no reviewers") {
+		t.Errorf("renderReviewers() = %q; want unknown", reviewers)
+	}
+}
+
+func TestDetailPanelLoadsLazily(t *testing.T) {
+	fetcher := loadFixtures(t)
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	if !m.isSplit() {
+		t.Fatal("isSplit() = false on a 160 column terminal")
+	}
+	if got := fetcher.PullRequestCalls("acme/widgets", 7); got != 1 {
+		t.Errorf("details of the highlighted entry fetched %d times; want 1", got)
+	}
+	if got := fetcher.PullRequestCalls("acme/gadgets", 12); got != 0 {
+		t.Errorf("details of an entry never highlighted fetched %d times; want 0", got)
+	}
+
+	view := m.View()
+	for _, want := range []string{"feature/widget-api → main", "+120", "−4", "3 files changed", "backend", "Adds the", "create", "Add widget API"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("split View() does not contain %q", want)
+		}
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyDown})
+	send(m, tea.KeyMsg{Type: tea.KeyUp})
+	if got := fetcher.PullRequestCalls("acme/gadgets", 12); got != 1 {
+		t.Errorf("details of the next entry fetched %d times; want 1", got)
+	}
+	if got := fetcher.PullRequestCalls("acme/widgets", 7); got != 1 {
+		t.Errorf("cached details fetched again; calls = %d, want 1", got)
+	}
+}
+
+func TestNarrowTerminalLoadsDetailsOnInfo(t *testing.T) {
+	fetcher := loadFixtures(t)
+	m := newTestModel(t, fetcher)
+	send(m, tea.WindowSizeMsg{Width: 100, Height: 40})
+	send(m, keyRunes("r"))
+
+	if m.isSplit() {
+		t.Fatal("isSplit() = true on a 100 column terminal")
+	}
+	if strings.Contains(m.View(), "feature/widget-api") {
+		t.Error("narrow View() shows the details next to the list")
+	}
+	if got := fetcher.PullRequestCalls("acme/widgets", 7); got != 0 {
+		t.Errorf("details fetched %d times before they were shown; want 0", got)
+	}
+
+	send(m, keyRunes("i"))
+	if got := fetcher.PullRequestCalls("acme/widgets", 7); got != 1 {
+		t.Errorf("details fetched %d times after i; want 1", got)
+	}
+	if !strings.Contains(m.View(), "feature/widget-api → main") {
+		t.Error("info View() does not show the branches")
+	}
+}
+
+const widgetDiff = `diff --git a/api.go b/api.go
+index 3b18e51..a9c4f2e 100644
+--- a/api.go
++++ b/api.go
+@@ -1,3 +1,4 @@
+ package widgets
+ 
++// ListWidgets lists every widget.
+ func ListWidgets() {}
+diff --git a/README.md b/README.md
+index 3b18e51..a9c4f2e 100644
+--- a/README.md
++++ b/README.md
+@@ -1,2 +1,2 @@
+ # Widgets
+-Old docs
++New docs
+`
+
+func TestDiffView(t *testing.T) {
+	fetcher := loadFixtures(t)
+	fetcher.SetDiff("acme/widgets", 7, widgetDiff)
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("v"))
+	if m.diff == nil {
+		t.Fatal("v did not open the diff")
+	}
+	view := m.View()
+	for _, want := range []string{"api.go", "README.md", "+1", "−1", "ListWidgets", "file 1/2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("diff View() does not contain %q", want)
+		}
+	}
+
+	send(m, keyRunes("]"))
+	if got := m.diff.file; got != 1 {
+		t.Errorf("file after ] = %d; want 1", got)
+	}
+	send(m, keyRunes("["))
+	if got := m.diff.file; got != 0 {
+		t.Errorf("file after [ = %d; want 0", got)
+	}
+
+	send(m, keyRunes("/"))
+	send(m, keyRunes("docs"))
+	send(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if got := len(m.diff.matches); got != 2 {
+		t.Errorf("search for docs matched %d lines; want 2", got)
+	}
+	if !strings.Contains(m.View(), "match 1/2") {
+		t.Error("diff View() does not show the match count")
+	}
+
+	send(m, keyRunes("q"))
+	if m.diff != nil || m.quit {
+		t.Error("q did not go back from the diff to the list")
+	}
+}
+
+func TestDiffReviewComments(t *testing.T) {
+	fetcher := loadFixtures(t)
+	fetcher.SetDiff("acme/widgets", 7, widgetDiff)
+	fetcher.SetTimeline("acme/widgets", 7, pullrequest.Timeline{Items: []pullrequest.TimelineItem{
+		{Kind: pullrequest.TimelineThread, Author: "monalisa", Thread: &pullrequest.Thread{
+			ID: "PRRT_1", Path: "api.go", Line: 3, Side: pullrequest.SideRight, Resolved: true,
+			Comments: []pullrequest.Comment{{Author: "monalisa", Body: "Document it"}, {Author: "octocat", Body: "Done"}},
+		}},
+		{Kind: pullrequest.TimelineThread, Author: "hubot", Thread: &pullrequest.Thread{
+			ID: "PRRT_2", Path: "README.md", OriginalLine: 5, Outdated: true,
+			Comments: []pullrequest.Comment{{Author: "hubot", Body: "Typo"}},
+		}},
+	}})
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+	send(m, keyRunes("v"))
+
+	view := m.View()
+	for _, want := range []string{"monalisa: Document it · ✓ resolved", "octocat: Done", "hubot: Typo · line 5 · outdated"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("diff View() does not contain %q", want)
+		}
+	}
+
+	send(m, keyRunes("c"))
+	if m.dialog != nil {
+		t.Fatal("c on a file header opened the comment dialog")
+	}
+
+	// api.go: header, hunk header, two context lines and the added line.
+	for range 4 {
+		send(m, keyRunes("j"))
+	}
+	send(m, keyRunes("c"))
+	if m.dialog == nil {
+		t.Fatal("c did not open the line comment dialog")
+	}
+	if !strings.Contains(m.View(), "Comment on api.go:3") {
+		t.Error("dialog does not show the line commented on")
+	}
+	send(m, keyRunes("Nice"))
+	send(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.dialog != nil || len(fetcher.Actions()) != 0 {
+		t.Fatal("adding a line comment did not keep it pending")
+	}
+	if view := m.View(); !strings.Contains(view, "pending: Nice") || !strings.Contains(view, "1 pending") {
+		t.Error("diff View() does not show the pending comment")
+	}
+
+	// README.md: header, outdated thread, hunk header, then # Widgets to +New docs.
+	send(m, keyRunes("]"))
+	for range 3 {
+		send(m, keyRunes("j"))
+	}
+	send(m, keyRunes("V"))
+	for range 2 {
+		send(m, keyRunes("j"))
+	}
+	send(m, keyRunes("c"))
+	send(m, keyRunes("Clarify"))
+	send(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if got := len(m.diff.pending); got != 2 {
+		t.Fatalf("len(pending) = %d; want 2", got)
+	}
+
+	send(m, keyRunes("x"))
+	if !strings.Contains(m.View(), "with 2 line comments") {
+		t.Error("review dialog does not list the pending comments")
+	}
+	send(m, keyRunes("Please fix"))
+	send(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	send(m, keyRunes("y"))
+	want := fake.Action{Name: "review", Repository: "acme/widgets", Number: 7, Args: []string{
+		"REQUEST_CHANGES", "Please fix", "api.go:3:RIGHT Nice", "README.md:1-2:RIGHT Clarify",
+	}}
+	if actions := fetcher.Actions(); len(actions) != 1 || !reflect.DeepEqual(actions[0], want) {
+		t.Errorf("Actions() = %+v; want %+v", actions, want)
+	}
+	if m.diff == nil || len(m.diff.pending) != 0 {
+		t.Error("submitted comments are still pending")
+	}
+	if !strings.Contains(m.View(), "✓ Requested changes on acme/widgets#7 with 2 comments") {
+		t.Error("View() does not report the review")
+	}
+}
+
+func TestDiffViewError(t *testing.T) {
+	m := newTestModel(t, loadFixtures(t))
+	send(m, keyRunes("r"))
+	send(m, keyRunes("v"))
+
+	if view := m.View(); !strings.Contains(view, "Failed to load diff") {
+		t.Errorf("diff View() = %q; want the error", view)
+	}
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.diff != nil {
+		t.Error("esc did not close the diff")
+	}
+}
+
+func TestSubmitReview(t *testing.T) {
+	fetcher := loadFixtures(t)
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("a"))
+	if m.dialog == nil {
+		t.Fatal("a did not open the review dialog")
+	}
+	send(m, keyRunes("LGTM"))
+	send(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !strings.Contains(m.View(), "Approve acme/widgets#7?") {
+		t.Error("View() does not ask for confirmation")
+	}
+	if got := len(fetcher.Actions()); got != 0 {
+		t.Fatalf("review submitted before confirming; actions = %d", got)
+	}
+
+	send(m, keyRunes("y"))
+	want := fake.Action{Name: "review", Repository: "acme/widgets", Number: 7, Args: []string{"APPROVE", "LGTM"}}
+	if actions := fetcher.Actions(); len(actions) != 1 || !reflect.DeepEqual(actions[0], want) {
+		t.Errorf("Actions() = %+v; want %+v", actions, want)
+	}
+	if m.dialog != nil {
+		t.Error("dialog still open after submitting")
+	}
+	if !strings.Contains(m.View(), "✓ Approved acme/widgets#7") {
+		t.Error("View() does not report the review")
+	}
+	if got := len(m.Entries[reviewRequests]); got != 1 {
+		t.Errorf("len(Entries[reviewRequests]) = %d after reviewing; want 1", got)
+	}
+	if entry, ok := m.SelectedEntry(); !ok || entry.PrNumber != 12 {
+		t.Errorf("SelectedEntry() = %+v; want the remaining review request", entry)
+	}
+	if got := fetcher.PullRequestCalls("acme/widgets", 7); got != 2 {
+		t.Errorf("reviewed pull request fetched %d times; want 2, once to show it and once to refresh it", got)
+	}
+}
+
+func TestSubmitReviewValidationAndErrors(t *testing.T) {
+	fetcher := loadFixtures(t)
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("x"))
+	send(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !strings.Contains(m.View(), "a comment is required") {
+		t.Error("requesting changes without a comment was not refused")
+	}
+
+	fetcher.FailActions("Can not request changes on your own pull request")
+	send(m, keyRunes("Needs tests"))
+	send(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	send(m, keyRunes("y"))
+	if m.dialog == nil {
+		t.Fatal("dialog closed although submitting failed")
+	}
+	if !strings.Contains(m.View(), "Can not request changes on your own pull request") {
+		t.Error("View() does not show the gh error")
+	}
+	if got := len(m.Entries[reviewRequests]); got != 2 {
+		t.Errorf("len(Entries[reviewRequests]) = %d after a failed review; want 2", got)
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dialog != nil {
+		t.Error("esc did not cancel the review")
+	}
+}
+
+func TestShortcutsIgnoredWhileFiltering(t *testing.T) {
+	fetcher := loadFixtures(t)
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("/"))
+	send(m, keyRunes("a"))
+	if m.dialog != nil {
+		t.Error("typing a filter opened the review dialog")
+	}
+	if got := m.List.FilterValue(); got != "a" {
+		t.Errorf("FilterValue() = %q; want a", got)
+	}
+}
+
+func TestMergeMyPR(t *testing.T) {
+	fetcher := loadFixtures(t)
+	fetcher.Set(fake.Fixture{
+		Query: category.Registry()[myPRs].Search().String(),
+		PullRequests: []*model.GithubPullRequest{{
+			PrNumber: 1, RepositoryNameWithOwner: "acme/widgets", Title: "Mine",
+			URL: "https://github.com/acme/widgets/pull/1", CheckStatus: "FAILURE", FailingChecks: []string{"lint"},
+		}},
+	})
+	fetcher.SetMergeStatus("acme/widgets", 1, &pullrequest.MergeStatus{
+		Methods:          []pullrequest.MergeMethod{pullrequest.MergeSquash, pullrequest.MergeCommit},
+		AutoMergeAllowed: true,
+		Mergeable:        "MERGEABLE",
+		MergeStateStatus: "UNSTABLE",
+	})
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("M"))
+	if m.dialog != nil {
+		t.Fatal("merge dialog opened outside of My PRs")
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyRight})
+	send(m, keyRunes("M"))
+	view := m.View()
+	for _, want := range []string{"✓ mergeable", "checks are not passing", "Squash and merge", "Create a merge commit", "Enable auto-merge (squash)", "Update branch from base"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("merge View() does not contain %q", want)
+		}
+	}
+	if strings.Contains(view, "Rebase and merge") {
+		t.Error("merge View() offers a method the repository does not allow")
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if view := m.View(); !strings.Contains(view, "checks are failing: lint") || !strings.Contains(view, "Squash and merge acme/widgets#1?") {
+		t.Errorf("confirmation View() = %q; want the failing checks and a question", view)
+	}
+	send(m, keyRunes("y"))
+
+	want := fake.Action{Name: "merge", Repository: "acme/widgets", Number: 1, Args: []string{"SQUASH"}}
+	if actions := fetcher.Actions(); len(actions) != 1 || !reflect.DeepEqual(actions[0], want) {
+		t.Errorf("Actions() = %+v; want %+v", actions, want)
+	}
+	if got := len(m.Entries[myPRs]); got != 0 {
+		t.Errorf("len(Entries[myPRs]) = %d after merging; want 0", got)
+	}
+	if !strings.Contains(m.View(), "✓ Squashed and merged acme/widgets#1") {
+		t.Error("View() does not report the merge")
+	}
+}
+
+func TestDisableAutoMerge(t *testing.T) {
+	fetcher := loadFixtures(t)
+	fetcher.Set(fake.Fixture{
+		Query: category.Registry()[myPRs].Search().String(),
+		PullRequests: []*model.GithubPullRequest{{
+			PrNumber: 1, RepositoryNameWithOwner: "acme/widgets", Title: "Mine", URL: "https://github.com/acme/widgets/pull/1",
+		}},
+	})
+	fetcher.SetMergeStatus("acme/widgets", 1, &pullrequest.MergeStatus{
+		Methods:          []pullrequest.MergeMethod{pullrequest.MergeSquash},
+		AutoMergeAllowed: true,
+		AutoMergeMethod:  pullrequest.MergeSquash,
+		Mergeable:        "MERGEABLE",
+		MergeStateStatus: "BLOCKED",
+	})
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+	send(m, tea.KeyMsg{Type: tea.KeyRight})
+	send(m, keyRunes("M"))
+
+	if !strings.Contains(m.View(), "enabled (squash)") {
+		t.Error("merge View() does not show that auto-merge is enabled")
+	}
+	send(m, tea.KeyMsg{Type: tea.KeyDown})
+	send(m, tea.KeyMsg{Type: tea.KeyEnter})
+	send(m, keyRunes("y"))
+
+	want := fake.Action{Name: "auto-merge", Repository: "acme/widgets", Number: 1, Args: []string{""}}
+	if actions := fetcher.Actions(); len(actions) != 1 || !reflect.DeepEqual(actions[0], want) {
+		t.Errorf("Actions() = %+v; want %+v", actions, want)
+	}
+	if got := len(m.Entries[myPRs]); got != 1 {
+		t.Errorf("len(Entries[myPRs]) = %d after disabling auto-merge; want 1", got)
+	}
+}
+
+func TestEditReviewers(t *testing.T) {
+	fetcher := loadFixtures(t)
+	fetcher.SetReviewerCandidates("acme/widgets", []pullrequest.Candidate{
+		{Login: "alice"}, {Login: "hubot"}, {Login: "monalisa"}, {Login: "octocat"},
+		{Login: "acme/docs", Name: "Docs", Team: true}, {Login: "acme/platform", Name: "Platform", Team: true},
+	})
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("e"))
+	if m.dialog == nil {
+		t.Fatal("e did not open the reviewers dialog")
+	}
+	send(m, keyRunes("r"))
+	send(m, tea.KeyMsg{Type: tea.KeyDown})
+	send(m, tea.KeyMsg{Type: tea.KeyDown})
+	send(m, keyRunes("d"))
+	if !strings.Contains(m.View(), "✓ Removed acme/platform from acme/widgets#7") {
+		t.Error("View() does not report the removal")
+	}
+	if d := m.dialog.(*reviewersDialog); d.index("acme/platform") >= 0 {
+		t.Errorf("reviewers = %+v; want acme/platform gone", d.reviewers)
+	}
+
+	send(m, keyRunes("+"))
+	view := m.View()
+	for _, want := range []string{"alice", "acme/docs"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("picker View() does not offer %q", want)
+		}
+	}
+	for _, unwanted := range []string{"octocat", "hubot", "monalisa"} {
+		if strings.Contains(view, unwanted) {
+			t.Errorf("picker View() offers %q, the author or an already requested reviewer", unwanted)
+		}
+	}
+	send(m, keyRunes("dcs"))
+	send(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	want := []fake.Action{
+		{Name: "request-reviews", Repository: "acme/widgets", Number: 7, Args: []string{"monalisa"}},
+		{Name: "remove-reviewers", Repository: "acme/widgets", Number: 7, Args: []string{"acme/platform"}},
+		{Name: "request-reviews", Repository: "acme/widgets", Number: 7, Args: []string{"acme/docs"}},
+	}
+	if actions := fetcher.Actions(); !reflect.DeepEqual(actions, want) {
+		t.Errorf("Actions() = %+v; want %+v", actions, want)
+	}
+	if view := m.View(); !strings.Contains(view, "acme/docs") || !strings.Contains(view, "✓ Requested a review from acme/docs") {
+		t.Errorf("View() = %q; want acme/docs requested", view)
+	}
+
+	fetcher.FailActions("HTTP 422: Reviews may only be requested from collaborators.")
+	send(m, keyRunes("d"))
+	if m.dialog == nil || !strings.Contains(m.View(), "Reviews may only be requested from collaborators") {
+		t.Error("View() does not show why the change failed")
+	}
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dialog != nil {
+		t.Error("esc did not close the reviewers dialog")
+	}
+}
+
+func TestToggleDraft(t *testing.T) {
+	fetcher := loadFixtures(t)
+	fetcher.Set(fake.Fixture{
+		Query: category.Registry()[draftPRs].Search().String(),
+		PullRequests: []*model.GithubPullRequest{{
+			PrNumber: 5, RepositoryNameWithOwner: "acme/widgets", Title: "WIP", URL: "https://github.com/acme/widgets/pull/5",
+		}},
+	})
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	m.CategoryIndex = draftPRs
+	m.List.SetItems(ItemsFromEntries(m.Entries[draftPRs]))
+	send(m, keyRunes("D"))
+	if !strings.Contains(m.View(), "Mark ready for review: acme/widgets#5") {
+		t.Fatalf("View() = %q; want to mark the draft ready", m.View())
+	}
+	send(m, keyRunes("y"))
+	if got := len(m.Entries[draftPRs]); got != 0 {
+		t.Errorf("len(Entries[draftPRs]) = %d after marking ready; want 0", got)
+	}
+
+	m.CategoryIndex = reviewRequests
+	m.List.SetItems(ItemsFromEntries(m.Entries[reviewRequests]))
+	send(m, keyRunes("D"))
+	if !strings.Contains(m.View(), "Convert to draft: acme/widgets#7") {
+		t.Fatalf("View() = %q; want to convert to draft", m.View())
+	}
+	send(m, keyRunes("y"))
+	if !strings.Contains(m.View(), "✓ Converted to draft acme/widgets#7") {
+		t.Error("View() does not report the conversion")
+	}
+	if got := len(m.Entries[reviewRequests]); got != 1 {
+		t.Errorf("len(Entries[reviewRequests]) = %d after converting to draft; want 1", got)
+	}
+
+	want := []fake.Action{
+		{Name: "set-draft", Repository: "acme/widgets", Number: 5, Args: []string{"false"}},
+		{Name: "set-draft", Repository: "acme/widgets", Number: 7, Args: []string{"true"}},
+	}
+	if actions := fetcher.Actions(); !reflect.DeepEqual(actions, want) {
+		t.Errorf("Actions() = %+v; want %+v", actions, want)
+	}
+}
+
+func TestEditLabelsAndAssignees(t *testing.T) {
+	fetcher := loadFixtures(t)
+	fetcher.SetLabels("acme/widgets", []model.Label{{Name: "backend", Color: "0e8a16"}, {Name: "bug", Color: "d73a4a"}, {Name: "docs", Color: "0075ca"}})
+	fetcher.SetAssignableUsers("acme/widgets", []string{"alice", "octocat"})
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("L"))
+	if view := m.View(); !strings.Contains(view, "bug") || !strings.Contains(view, "docs") {
+		t.Fatalf("View() = %q; want every label", view)
+	}
+	if p := m.dialog.(*triageDialog).picker; !reflect.DeepEqual(p.picked(), []string{"backend"}) {
+		t.Errorf("picked() = %v; want the current label", p.picked())
+	}
+	send(m, tea.KeyMsg{Type: tea.KeyTab})
+	send(m, tea.KeyMsg{Type: tea.KeyDown})
+	send(m, tea.KeyMsg{Type: tea.KeyTab})
+	send(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(m.View(), "✓ Updated the labels of acme/widgets#7: +bug −backend") {
+		t.Error("View() does not report the label change")
+	}
+
+	send(m, keyRunes("A"))
+	send(m, keyRunes("alc"))
+	send(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	send(m, keyRunes("@"))
+	if !strings.Contains(m.View(), "✓ Assigned you to acme/widgets#7") {
+		t.Error("View() does not report the assignment")
+	}
+
+	want := []fake.Action{
+		{Name: "labels", Repository: "acme/widgets", Number: 7, Args: []string{"+bug", "-backend"}},
+		{Name: "assignees", Repository: "acme/widgets", Number: 7, Args: []string{"+alice"}},
+		{Name: "assignees", Repository: "acme/widgets", Number: 7, Args: []string{"+@me"}},
+	}
+	if actions := fetcher.Actions(); !reflect.DeepEqual(actions, want) {
+		t.Errorf("Actions() = %+v; want %+v", actions, want)
+	}
+
+	fetcher.FailActions("HTTP 403: Resource not accessible by integration")
+	send(m, keyRunes("@"))
+	if m.dialog == nil || !strings.Contains(m.View(), "Resource not accessible by integration") {
+		t.Error("View() does not show why assigning failed")
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	fetcher := loadFixtures(t)
+	created := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	fetcher.SetTimeline("acme/widgets", 7, pullrequest.Timeline{Items: []pullrequest.TimelineItem{
+		{Kind: pullrequest.TimelineDescription, Author: "octocat", Body: "Adds the widget API.", CreatedAt: created},
+		{Kind: pullrequest.TimelineThread, Author: "monalisa", CreatedAt: created.Add(time.Hour), Thread: &pullrequest.Thread{
+			ID: "PRRT_1", Path: "widget.go", StartLine: 10, Line: 12, Resolved: true,
+			Comments: []pullrequest.Comment{{Author: "monalisa", Body: "Why a pointer?", CreatedAt: created.Add(time.Hour)}},
+		}},
+		{Kind: pullrequest.TimelineComment, Author: "hubot", Body: "Deployed to staging.", CreatedAt: created.Add(2 * time.Hour)},
+	}, Truncated: []pullrequest.Truncation{{What: "comments", Shown: 100, Total: 250}}})
+	m := newTestModel(t, fetcher)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("t"))
+	view := m.View()
+	for _, want := range []string{"showing 100 of 250 comments", "octocat opened this pull request", "monalisa commented on widget.go:10-12", "✓ resolved", "pointer?", "staging."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("timeline View() does not contain %q", want)
+		}
+	}
+
+	send(m, keyRunes("c"))
+	send(m, keyRunes("Thanks"))
+	send(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	send(m, keyRunes("y"))
+	if !strings.Contains(m.View(), "✓ Commented on acme/widgets#7") || m.timeline == nil {
+		t.Error("View() does not report the comment on the timeline")
+	}
+
+	send(m, keyRunes("]"))
+	send(m, keyRunes("r"))
+	if !strings.Contains(m.View(), "Reply on widget.go:10-12") {
+		t.Fatalf("View() = %q; want the reply dialog", m.View())
+	}
+	send(m, keyRunes("Done"))
+	send(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	send(m, keyRunes("y"))
+
+	want := []fake.Action{
+		{Name: "comment", Repository: "acme/widgets", Number: 7, Args: []string{"Thanks"}},
+		{Name: "reply", Args: []string{"PRRT_1", "Done"}},
+	}
+	if actions := fetcher.Actions(); !reflect.DeepEqual(actions, want) {
+		t.Errorf("Actions() = %+v; want %+v", actions, want)
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.timeline != nil {
+		t.Error("esc did not close the timeline")
+	}
+}
+
+// fakeCheckouter checks out into /work without running git or gh.
+type fakeCheckouter struct {
+	cloned  bool
+	changes []string
+	repoErr error
+	fail    error
+	// checkouts records the stash argument of every CheckOut call.
+	checkouts []bool
+	// restores records the pull requests changes were restored for.
+	restores []int
+}
+
+func (c *fakeCheckouter) RepoDir(repository string) (string, bool, error) {
+	return "/work/" + repository, c.cloned, c.repoErr
+}
+
+func (c *fakeCheckouter) Prepare(ctx context.Context, repository string, number int, progress io.Writer) (string, error) {
+	if !c.cloned {
+		fmt.Fprint(progress, "Cloning into '/work/acme/widgets'...\nReceiving objects:  50%\rReceiving objects: 100%\n")
+		c.cloned = true
+	}
+	return "/work/" + repository, nil
+}
+
+func (c *fakeCheckouter) DirtyFiles(ctx context.Context, dir string) ([]string, error) {
+	return c.changes, nil
+}
+
+func (c *fakeCheckouter) CheckOut(ctx context.Context, dir, repository string, number int, stash bool, progress io.Writer) error {
+	c.checkouts = append(c.checkouts, stash)
+	fmt.Fprintf(progress, "Switched to branch 'feature'\n")
+	return c.fail
+}
+
+func (c *fakeCheckouter) Restore(ctx context.Context, repository string, number int, progress io.Writer) (string, error) {
+	if c.fail != nil {
+		return "", c.fail
+	}
+	c.restores = append(c.restores, number)
+	fmt.Fprintf(progress, "Switching back to main\n")
+	return "/work/" + repository, nil
+}
+
+func TestCheckout(t *testing.T) {
+	checkouter := &fakeCheckouter{changes: []string{"?? notes.txt"}}
+	m := newTestModel(t, loadFixtures(t))
+	m.SetCheckouter(checkouter)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("c"))
+	if m.dialog == nil || m.quit {
+		t.Fatal("c did not open the checkout dialog")
+	}
+	if !strings.Contains(m.View(), "Clone it into /work/acme/widgets?") {
+		t.Error("View() does not ask before cloning")
+	}
+
+	send(m, keyRunes("y"))
+	view := m.View()
+	for _, want := range []string{"Receiving objects: 100%", "uncommitted changes", "?? notes.txt"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("checkout View() does not contain %q", want)
+		}
+	}
+	if strings.Contains(view, "50%") {
+		t.Error("progress line was not overwritten after a carriage return")
+	}
+
+	send(m, keyRunes("s"))
+	if !reflect.DeepEqual(checkouter.checkouts, []bool{true}) {
+		t.Errorf("checkouts = %v; want one, stashing", checkouter.checkouts)
+	}
+	if view := m.View(); !strings.Contains(view, "✓ Checked out #7 in /work/acme/widgets") || !strings.Contains(view, "Switched to branch") {
+		t.Errorf("View() = %q; want the finished checkout", view)
+	}
+	if !strings.Contains(m.View(), "press U on this pull request to switch back") {
+		t.Error("View() does not tell how to restore the stashed changes")
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dialog != nil || m.quit {
+		t.Error("esc did not go back to the list")
+	}
+
+	// A clean clone is checked out without asking; failures are shown.
+	checkouter.changes = nil
+	checkouter.fail = fmt.Errorf("could not switch branches")
+	send(m, keyRunes("c"))
+	if !strings.Contains(m.View(), "could not switch branches") {
+		t.Error("View() does not show the failed checkout")
+	}
+	if strings.Contains(m.View(), "press U") {
+		t.Error("View() tells how to restore changes that were not stashed")
+	}
+	if len(checkouter.checkouts) != 2 {
+		t.Errorf("len(checkouts) = %d; want 2", len(checkouter.checkouts))
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	checkouter.changes = []string{" M api.go"}
+	send(m, keyRunes("c"))
+	send(m, keyRunes("c"))
+	if m.dialog != nil || len(checkouter.checkouts) != 2 {
+		t.Error("cancelling at the uncommitted changes still checked out")
+	}
+
+	// The changes stay stashed when the checkout fails after stashing them.
+	send(m, keyRunes("c"))
+	send(m, keyRunes("s"))
+	if view := m.View(); !strings.Contains(view, "could not switch branches") || !strings.Contains(view, "press U on this pull request to switch back") {
+		t.Errorf("View() = %q; want the failed checkout and how to restore the stashed changes", view)
+	}
+
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	checkouter.repoErr = fmt.Errorf(`unsafe path: invalid owner ".." in repository "../widgets"`)
+	send(m, keyRunes("c"))
+	if !strings.Contains(m.View(), "invalid owner") || len(checkouter.checkouts) != 3 {
+		t.Error("View() does not show why the repository cannot be checked out")
+	}
+}
+
+func TestRestoreStashedChanges(t *testing.T) {
+	checkouter := &fakeCheckouter{cloned: true}
+	m := newTestModel(t, loadFixtures(t))
+	m.SetCheckouter(checkouter)
+	send(m, keyRunes("r"))
+
+	send(m, keyRunes("U"))
+	if !reflect.DeepEqual(checkouter.restores, []int{7}) {
+		t.Errorf("restores = %v; want #7", checkouter.restores)
+	}
+	view := m.View()
+	for _, want := range []string{"Switch back from acme/widgets#7", "Switching back to main", "✓ Restored your changes in /work/acme/widgets"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("restore View() does not contain %q", want)
+		}
+	}
+	send(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dialog != nil {
+		t.Error("esc did not close the restore dialog")
+	}
+
+	checkouter.fail = fmt.Errorf(`no stash labelled "gh-rr: before checking out acme/widgets#7"`)
+	send(m, keyRunes("U"))
+	if !strings.Contains(m.View(), "no stash labelled") {
+		t.Error("View() does not show why restoring failed")
+	}
+}
@@ -1,15 +1,16 @@
 package utils
 
 import (
-	"bufio"
+	"bytes"
 	"context"
 	"fmt"
-	"log"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"syscall"
+	"sync"
 
 	"github.com/cli/go-gh/v2"
 	"github.com/pkg/errors"
@@ -45,50 +46,374 @@ func ExpandHome(p string) string {
    return p
 }
 
-func CloneAndCheckout(ctx context.Context, repositoryNameWithOwner string, prNumber int, baseDir string) {
-	log.Println("Cloning or Checking out", repositoryNameWithOwner, "to", baseDir)
-	dir := filepath.Join(baseDir, repositoryNameWithOwner)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		reader := bufio.NewReader(os.Stdin)
-		log.Printf("Clone %s into %s? [Y/n]: ", repositoryNameWithOwner, dir)
-		resp, _ := reader.ReadString('\n')
-		resp = strings.TrimSpace(resp)
-		if resp != "" && strings.ToLower(resp) == "n" && !strings.Contains(strings.ToLower(resp), "y") {
-			fmt.Println("Skipping clone.")
-			return
+// WorktreesDir is the directory inside a clone that holds the worktrees of its pull requests.
+const WorktreesDir = ".worktrees"
+
+// WorktreeDir returns the worktree of pull request prNumber in the clone at
+// dir, failing when WorktreesDir is a symlink leading out of the clone.
+func WorktreeDir(dir string, prNumber int) (string, error) {
+	return SafeJoin(dir, filepath.Join(WorktreesDir, "pr-"+strconv.Itoa(prNumber)))
+}
+
+// Checkout clones repositories under BaseDir and checks pull requests out in
+// them, reusing existing clones found elsewhere. Commands write their output
+// to a progress writer as they run.
+type Checkout struct {
+	BaseDir string
+	// Layout is where a repository is cloned below BaseDir, DefaultLayout when empty.
+	Layout string
+	// Paths maps owner/repo to an existing clone, taking precedence over everything else.
+	Paths map[string]string
+	// Roots are searched for existing clones by remote URL the first time a
+	// repository is not found at its place in the layout.
+	Roots []string
+	// Worktree gives every pull request its own git worktree, reused when it is
+	// checked out again, leaving the branch of the clone alone.
+	Worktree bool
+
+	indexOnce sync.Once
+	index     map[string]IndexedClone
+}
+
+// RepoDir returns where the repository is cloned and whether it is there
+// already: its path in Paths, its place in the layout if it was cloned there,
+// or an existing clone in Roots. Repositories not found anywhere are cloned
+// into the layout. Paths are trusted as configured but must be absolute;
+// invalid repository names, layout paths leading out of BaseDir and clones in
+// Roots that resolve to outside of their root fail with ErrUnsafePath.
+func (c *Checkout) RepoDir(repositoryNameWithOwner string) (string, bool, error) {
+	if err := ValidateRepository(repositoryNameWithOwner); err != nil {
+		return "", false, err
+	}
+	for repository, dir := range c.Paths {
+		if strings.EqualFold(repository, repositoryNameWithOwner) {
+			dir = filepath.Clean(ExpandHome(dir))
+			if !filepath.IsAbs(dir) {
+				return "", false, fmt.Errorf("%w: path %q of %s is not absolute", ErrUnsafePath, dir, repositoryNameWithOwner)
+			}
+			return dir, isDir(dir), nil
 		}
+	}
 
-		log.Printf("Creating Directory into %s\n", dir)
+	dir, err := SafeJoin(c.BaseDir, ExpandLayout(c.Layout, repositoryNameWithOwner))
+	if err != nil {
+		return "", false, err
+	}
+	if isDir(dir) {
+		return dir, true, nil
+	}
+	c.indexOnce.Do(func() { c.index = IndexClones(c.Roots) })
+	if found, ok := c.index[strings.ToLower(repositoryNameWithOwner)]; ok && isDir(found.Dir) {
+		// The clone may have been replaced by a symlink since it was indexed.
+		if !resolvedWithin(found.Root, found.Dir) {
+			return "", false, fmt.Errorf("%w: %s resolves to outside of %s", ErrUnsafePath, found.Dir, found.Root)
+		}
+		return found.Dir, true, nil
+	}
+	return dir, false, nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// Prepare clones the repository unless it is there already and, in worktree
+// mode, adds the worktree of the pull request. It returns the directory to
+// check the pull request out in.
+func (c *Checkout) Prepare(ctx context.Context, repositoryNameWithOwner string, prNumber int, progress io.Writer) (string, error) {
+	dir, cloned, err := c.RepoDir(repositoryNameWithOwner)
+	if err != nil {
+		return "", err
+	}
+	if !cloned {
 		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
-			log.Println(err)
+			return "", errors.Wrap(err, "creating clone directory")
+		}
+		fmt.Fprintf(progress, "Cloning %s into %s\n", repositoryNameWithOwner, dir)
+		if err := runGh(ctx, "", progress, "repo", "clone", repositoryNameWithOwner, dir, "--", "--progress"); err != nil {
+			return "", errors.Wrapf(err, "cloning %s", repositoryNameWithOwner)
 		}
+	}
+	if !c.Worktree {
+		return dir, nil
+	}
 
-		log.Printf("Cloning %s into %s\n", repositoryNameWithOwner, dir)
-		if _, stderr, err := gh.ExecContext(ctx, "repo", "clone", repositoryNameWithOwner, dir); err != nil {
-			log.Panicln(stderr.String(), err)
+	worktreeDir, err := WorktreeDir(dir, prNumber)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(worktreeDir); err == nil {
+		fmt.Fprintf(progress, "Reusing worktree %s\n", worktreeDir)
+		return worktreeDir, nil
+	}
+	fmt.Fprintf(progress, "Adding worktree %s\n", worktreeDir)
+	if err := addWorktree(ctx, dir, worktreeDir); err != nil {
+		return "", err
+	}
+	return worktreeDir, nil
+}
+
+// CheckOut checks the pull request out in dir with gh pr checkout, stashing
+// uncommitted changes first with stash along with the branch they were on, so
+// that Restore can bring both back.
+func (c *Checkout) CheckOut(ctx context.Context, dir, repositoryNameWithOwner string, prNumber int, stash bool, progress io.Writer) error {
+	if stash {
+		if err := stashChanges(ctx, dir, StashLabel(repositoryNameWithOwner, prNumber), progress); err != nil {
+			return err
 		}
-	} else {
-		log.Println("Found existing repository", repositoryNameWithOwner)
 	}
+	fmt.Fprintf(progress, "Checking out %s#%d\n", repositoryNameWithOwner, prNumber)
+	if err := runGh(ctx, dir, progress, "pr", "checkout", strconv.Itoa(prNumber)); err != nil {
+		return errors.Wrapf(err, "checking out #%d", prNumber)
+	}
+	return nil
+}
 
-	log.Printf("Changing working directory to %s\n", dir)
-	if err := os.Chdir(dir); err != nil {
-		log.Panicln(errors.Wrap(err, "chdir"))
+// Restore switches the checkout of the pull request back to the branch it was
+// on when CheckOut stashed its changes, then applies and drops that stash. It
+// returns the checkout.
+func (c *Checkout) Restore(ctx context.Context, repositoryNameWithOwner string, prNumber int, progress io.Writer) (string, error) {
+	dir, cloned, err := c.RepoDir(repositoryNameWithOwner)
+	if err != nil {
+		return "", err
+	}
+	if !cloned {
+		return "", fmt.Errorf("%s is not cloned in %s", repositoryNameWithOwner, dir)
+	}
+	if c.Worktree {
+		if dir, err = WorktreeDir(dir, prNumber); err != nil {
+			return "", err
+		}
 	}
+	if err := restoreStash(ctx, dir, StashLabel(repositoryNameWithOwner, prNumber), progress); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// DirtyFiles lists the uncommitted changes and untracked files at dir, the
+// clone or worktree returned by Prepare that CheckOut would check the pull
+// request out in, so they can be stashed first.
+func (*Checkout) DirtyFiles(ctx context.Context, dir string) ([]string, error) {
+	return DirtyFiles(ctx, dir)
+}
 
-	// Checkout PR
-	log.Println("Checking out", repositoryNameWithOwner+"/"+strconv.Itoa(prNumber))
-	if _, stderr, err := gh.ExecContext(ctx, "pr", "checkout", strconv.Itoa(prNumber)); err != nil {
-		log.Panicln(stderr.String(), err)
+// DirtyFiles lists the uncommitted changes and untracked files of the
+// checkout at dir in git status --short format.
+func DirtyFiles(ctx context.Context, dir string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain", "--untracked-files=normal")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "git status: %s", strings.TrimSpace(stderr.String()))
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) != "" {
+			files = append(files, line)
+		}
 	}
+	return files, nil
+}
+
+// StashLabel is the message of the stash gh-rr makes before checking out a
+// pull request, so that it can be told apart in git stash list.
+func StashLabel(repositoryNameWithOwner string, prNumber int) string {
+	return fmt.Sprintf("gh-rr: before checking out %s#%d", repositoryNameWithOwner, prNumber)
+}
 
-	log.Printf("Checked out PR #%d in %s\n", prNumber, dir)
-	shell := os.Getenv("SHELL")
-	if shell == "" {
-		shell = "/bin/sh"
+// stashMessage is the message of a stash labelled label taken on branch.
+func stashMessage(label, branch string) string {
+	return label + ", on " + branch
+}
+
+// Stash stashes the changes and untracked files of the checkout at dir with message.
+func Stash(ctx context.Context, dir, message string) error {
+	return runGit(ctx, dir, "stash", "push", "--include-untracked", "--message", message)
+}
+
+// stashChanges stashes the changes of the checkout at dir labelled label,
+// recording the branch they were on in the message of the stash.
+func stashChanges(ctx context.Context, dir, label string, progress io.Writer) error {
+	branch, err := currentBranch(ctx, dir)
+	if err != nil {
+		return err
+	}
+	message := stashMessage(label, branch)
+	if err := Stash(ctx, dir, message); err != nil {
+		return err
 	}
-	// replace current process with a shell in the checked-out repo
-	if err := syscall.Exec(shell, []string{shell}, os.Environ()); err != nil {
-		log.Panicln(errors.Wrap(err, "exec shell"))
+	fmt.Fprintf(progress, "Stashed your changes as %q\n", message)
+	return nil
+}
+
+// restoreStash switches the checkout at dir back to the branch the newest
+// stash labelled label was taken on, then applies and drops that stash.
+func restoreStash(ctx context.Context, dir, label string, progress io.Writer) error {
+	ref, branch, err := FindStash(ctx, dir, label)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(progress, "Switching back to %s\n", branch)
+	if err := runGit(ctx, dir, "checkout", branch, "--"); err != nil {
+		return err
+	}
+	fmt.Fprintf(progress, "Restoring your changes from %s\n", ref)
+	if err := runGit(ctx, dir, "stash", "apply", ref); err != nil {
+		return err
+	}
+	return runGit(ctx, dir, "stash", "drop", ref)
+}
+
+// FindStash returns the newest stash of the checkout at dir labelled label,
+// as a stash@{n} reference, with the branch or commit it was taken on.
+func FindStash(ctx context.Context, dir, label string) (string, string, error) {
+	list, err := gitOutput(ctx, dir, "stash", "list", "--format=%gd%x00%gs")
+	if err != nil {
+		return "", "", err
+	}
+	for _, line := range strings.Split(list, "\n") {
+		ref, subject, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		// git records stashes as "On <branch>: <message>"; branch names have no colons.
+		on, message, ok := strings.Cut(subject, ": ")
+		if !ok {
+			continue
+		}
+		if branch, ok := strings.CutPrefix(message, stashMessage(label, "")); ok && branch != "" {
+			return ref, branch, nil
+		}
+		if branch, ok := strings.CutPrefix(on, "On "); ok && message == label && branch != "(no branch)" {
+			return ref, branch, nil
+		}
+	}
+	return "", "", fmt.Errorf("no stash labelled %q in %s", label, dir)
+}
+
+// currentBranch is the branch checked out at dir, or its commit when HEAD is detached.
+func currentBranch(ctx context.Context, dir string) (string, error) {
+	if branch, err := gitOutput(ctx, dir, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		return branch, nil
+	}
+	return gitOutput(ctx, dir, "rev-parse", "HEAD")
+}
+
+// addWorktree adds a worktree at worktreeDir to the clone at dir, detached at
+// the current commit until gh pr checkout switches it to the pull request.
+// Worktrees removed by hand are pruned first so their path can be reused.
+func addWorktree(ctx context.Context, dir, worktreeDir string) error {
+	if err := excludeWorktrees(dir); err != nil {
+		return err
+	}
+	if err := runGit(ctx, dir, "worktree", "prune"); err != nil {
+		return err
+	}
+	return runGit(ctx, dir, "worktree", "add", "--detach", worktreeDir)
+}
+
+// excludeWorktrees keeps WorktreesDir out of git status in the clone at dir
+// through its .git/info/exclude file.
+func excludeWorktrees(dir string) error {
+	path := filepath.Join(dir, ".git", "info", "exclude")
+	pattern := "/" + WorktreesDir + "/"
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "reading git excludes")
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		pattern = "\n" + pattern
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "writing git excludes")
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "writing git excludes")
+	}
+	defer file.Close()
+	if _, err := file.WriteString(pattern + "\n"); err != nil {
+		return errors.Wrap(err, "writing git excludes")
+	}
+	return nil
+}
+
+// runGit runs git with args in dir, returning its stderr in the error.
+func runGit(ctx context.Context, dir string, args ...string) error {
+	_, err := gitOutput(ctx, dir, args...)
+	return err
+}
+
+// gitOutput runs git with args in dir and returns its output without the
+// trailing newline, or its stderr in the error.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// runGh runs gh with args in dir, streaming its output to progress. The error
+// ends with the last line it wrote.
+func runGh(ctx context.Context, dir string, progress io.Writer, args ...string) error {
+	path, err := gh.Path()
+	if err != nil {
+		return err
+	}
+	output := &commandOutput{progress: progress}
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Dir = dir
+	// The same writer for both keeps exec from writing to it concurrently.
+	cmd.Stdout, cmd.Stderr = output, output
+	if err := cmd.Run(); err != nil {
+		if line := output.lastLine(); line != "" {
+			return errors.Wrap(err, line)
+		}
+		return err
+	}
+	return nil
+}
+
+// commandOutputTail is how much of the output of a command commandOutput keeps.
+const commandOutputTail = 4096
+
+// commandOutput passes the output of a command on to progress, keeping its end.
+type commandOutput struct {
+	progress io.Writer
+	tail     []byte
+}
+
+func (o *commandOutput) Write(p []byte) (int, error) {
+	o.tail = append(o.tail, p...)
+	if len(o.tail) > commandOutputTail {
+		o.tail = o.tail[len(o.tail)-commandOutputTail:]
+	}
+	return o.progress.Write(p)
+}
+
+// lastLine is the last non-empty line written, progress updates included.
+func (o *commandOutput) lastLine() string {
+	lines := strings.FieldsFunc(string(o.tail), func(r rune) bool { return r == '\n' || r == '\r' })
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
 	}
+	return ""
 }
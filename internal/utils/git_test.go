@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a git repository with a single commit.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"config", "user.name", "gh-rr"},
+		{"config", "user.email", "gh-rr@example.com"},
+		{"commit", "--quiet", "--allow-empty", "-m", "initial"},
+	} {
+		if err := runGit(context.Background(), dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestAddWorktree(t *testing.T) {
+	ctx := context.Background()
+	dir := initRepo(t)
+	worktreeDir, err := WorktreeDir(dir, 123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, ".worktrees", "pr-123"); worktreeDir != want {
+		t.Errorf("WorktreeDir() = %q; want %q", worktreeDir, want)
+	}
+
+	if err := addWorktree(ctx, dir, worktreeDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(worktreeDir, ".git")); err != nil {
+		t.Errorf("worktree not created: %v", err)
+	}
+	status, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 0 {
+		t.Errorf("git status of the clone = %q; want it clean", status)
+	}
+
+	// A worktree deleted by hand can be added again.
+	if err := os.RemoveAll(worktreeDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := addWorktree(ctx, dir, worktreeDir); err != nil {
+		t.Errorf("adding a worktree removed by hand: %v", err)
+	}
+}
+
+func TestExcludeWorktrees(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".git", "info", "exclude")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("*.log"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if err := excludeWorktrees(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "*.log\n/.worktrees/\n"; got != want {
+		t.Errorf("exclude = %q; want %q", got, want)
+	}
+}
+
+func TestDirtyFilesAndStash(t *testing.T) {
+	ctx := context.Background()
+	dir := initRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("wip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := DirtyFiles(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != "?? notes.txt" {
+		t.Errorf("DirtyFiles() = %q; want the untracked file", files)
+	}
+
+	label := StashLabel("acme/widgets", 7)
+	if err := Stash(ctx, dir, label); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := DirtyFiles(ctx, dir); len(files) != 0 {
+		t.Errorf("DirtyFiles() after stashing = %q; want none", files)
+	}
+	list, err := exec.Command("git", "-C", dir, "stash", "list").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(list), "gh-rr: before checking out acme/widgets#7") {
+		t.Errorf("git stash list = %q; want the labelled stash", list)
+	}
+
+	if err := runGit(ctx, dir, "stash", "pop"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("stash did not restore the untracked file: %v", err)
+	}
+}
+
+func TestRestoreStash(t *testing.T) {
+	ctx := context.Background()
+	dir := initRepo(t)
+	branch, err := currentBranch(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The newer stash of #70 must not be taken for the one of #7.
+	for _, stash := range []struct {
+		number int
+		file   string
+	}{{7, "notes.txt"}, {70, "other.txt"}} {
+		if err := os.WriteFile(filepath.Join(dir, stash.file), []byte("wip"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := stashChanges(ctx, dir, StashLabel("acme/widgets", stash.number), io.Discard); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := runGit(ctx, dir, "checkout", "--quiet", "-b", "pr-7"); err != nil {
+		t.Fatal(err)
+	}
+
+	var progress strings.Builder
+	if err := restoreStash(ctx, dir, StashLabel("acme/widgets", 7), &progress); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := currentBranch(ctx, dir); got != branch {
+		t.Errorf("branch after restoreStash() = %q; want %q", got, branch)
+	}
+	if files, _ := DirtyFiles(ctx, dir); !reflect.DeepEqual(files, []string{"?? notes.txt"}) {
+		t.Errorf("DirtyFiles() after restoreStash() = %q; want the changes stashed for #7", files)
+	}
+	if !strings.Contains(progress.String(), "Switching back to "+branch) {
+		t.Errorf("progress = %q; want the branch switched back to", progress.String())
+	}
+
+	if _, _, err := FindStash(ctx, dir, StashLabel("acme/widgets", 7)); err == nil {
+		t.Error("the stash of #7 was not dropped")
+	}
+	if _, found, err := FindStash(ctx, dir, StashLabel("acme/widgets", 70)); err != nil || found != branch {
+		t.Errorf("FindStash(#70) = %q, %v; want the stash on %s kept", found, err, branch)
+	}
+	if err := restoreStash(ctx, dir, StashLabel("acme/widgets", 7), io.Discard); err == nil {
+		t.Error("restoreStash() without a stash succeeded; want an error")
+	}
+}
+
+func TestCommandOutputLastLine(t *testing.T) {
+	var progress strings.Builder
+	output := &commandOutput{progress: &progress}
+	fmt.Fprint(output, "Cloning into 'widgets'...\nReceiving objects:  50%\rReceiving objects: 100%\r\nfatal: could not read from remote\n\n")
+
+	if got, want := output.lastLine(), "fatal: could not read from remote"; got != want {
+		t.Errorf("lastLine() = %q; want %q", got, want)
+	}
+	if !strings.Contains(progress.String(), "Receiving objects:  50%\r") {
+		t.Error("output was not passed on to progress as is")
+	}
+}
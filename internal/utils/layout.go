@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GitHubHost is the host of the repositories gh-rr checks out.
+const GitHubHost = "github.com"
+
+// DefaultLayout clones repositories into BASE_DIR/owner/repo.
+const DefaultLayout = "{owner}/{repo}"
+
+// indexDepth is how deep IndexClones looks for clones below a root, enough
+// for layouts like root/github.com/owner/repo.
+const indexDepth = 4
+
+// ValidateLayout checks that layout is a relative path naming a directory per
+// repository with the {host}, {owner} and {repo} placeholders.
+func ValidateLayout(layout string) error {
+	if !strings.Contains(layout, "{repo}") {
+		return fmt.Errorf("layout %q must contain {repo}", layout)
+	}
+	if filepath.IsAbs(layout) || strings.HasPrefix(layout, "~") {
+		return fmt.Errorf("layout %q must be relative to the base directory", layout)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(layout), "/") {
+		if part == ".." {
+			return fmt.Errorf("layout %q must not contain ..", layout)
+		}
+	}
+	return nil
+}
+
+// ExpandLayout fills the placeholders of layout in for repositoryNameWithOwner.
+func ExpandLayout(layout, repositoryNameWithOwner string) string {
+	if layout == "" {
+		layout = DefaultLayout
+	}
+	owner, repo, _ := strings.Cut(repositoryNameWithOwner, "/")
+	return filepath.FromSlash(strings.NewReplacer(
+		"{host}", GitHubHost,
+		"{owner}", owner,
+		"{repo}", repo,
+	).Replace(layout))
+}
+
+// IndexedClone is a clone IndexClones found below Root.
+type IndexedClone struct {
+	Dir  string
+	Root string
+}
+
+// IndexClones looks for git clones in roots and maps the owner/repo of every
+// GitHub remote, lowercased, to the clone. Clones whose origin is the
+// repository win over ones that only have it as another remote, such as the
+// upstream of a fork; otherwise the first clone found wins.
+func IndexClones(roots []string) map[string]IndexedClone {
+	index := map[string]IndexedClone{}
+	origins := map[string]bool{}
+	for _, root := range roots {
+		root = filepath.Clean(ExpandHome(root))
+		walkClones(root, indexDepth, func(dir string) {
+			for remote, url := range remoteURLs(dir) {
+				repository, ok := RepositoryFromURL(url)
+				if !ok {
+					continue
+				}
+				key := strings.ToLower(repository)
+				if _, found := index[key]; !found || remote == "origin" && !origins[key] {
+					index[key] = IndexedClone{Dir: dir, Root: root}
+					origins[key] = remote == "origin"
+				}
+			}
+		})
+	}
+	return index
+}
+
+// walkClones calls found for every git clone up to depth levels below dir,
+// in lexical order, without descending into clones or hidden directories.
+func walkClones(dir string, depth int, found func(dir string)) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		found(dir)
+		return
+	}
+	if depth == 0 {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			walkClones(filepath.Join(dir, entry.Name()), depth-1, found)
+		}
+	}
+}
+
+// remoteURLs reads the URL of every remote from the git config of the clone at dir.
+func remoteURLs(dir string) map[string]string {
+	file, err := os.Open(filepath.Join(dir, ".git", "config"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	urls := map[string]string{}
+	remote := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			remote = ""
+			if name, ok := strings.CutPrefix(line, `[remote "`); ok {
+				remote = strings.TrimSuffix(name, `"]`)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if remote != "" && ok && strings.TrimSpace(key) == "url" {
+			urls[remote] = strings.TrimSpace(value)
+		}
+	}
+	return urls
+}
+
+// RepositoryFromURL returns the owner/repo of a GitHub remote URL in the
+// https, ssh or scp-like git@github.com:owner/repo form.
+func RepositoryFromURL(url string) (string, bool) {
+	var rest string
+	switch {
+	case strings.HasPrefix(url, "git@"+GitHubHost+":"):
+		rest = strings.TrimPrefix(url, "git@"+GitHubHost+":")
+	default:
+		_, after, ok := strings.Cut(url, "://")
+		if !ok {
+			return "", false
+		}
+		host, p, ok := strings.Cut(after, "/")
+		if !ok {
+			return "", false
+		}
+		if _, h, ok := strings.Cut(host, "@"); ok {
+			host = h
+		}
+		if strings.TrimSuffix(host, ":22") != GitHubHost {
+			return "", false
+		}
+		rest = p
+	}
+
+	rest = strings.TrimSuffix(strings.TrimSuffix(rest, "/"), ".git")
+	owner, repo, ok := strings.Cut(rest, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", false
+	}
+	return owner + "/" + repo, true
+}
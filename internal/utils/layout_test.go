@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeClone creates a fake clone at dir whose git config lists remotes.
+func writeClone(t *testing.T, dir string, remotes map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := "[core]\n\tbare = false\n"
+	for name, url := range remotes {
+		config += "[remote \"" + name + "\"]\n\turl = " + url + "\n\tfetch = +refs/heads/*:refs/remotes/" + name + "/*\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "config"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpandLayout(t *testing.T) {
+	cases := map[string]string{
+		"":                      filepath.Join("acme", "widgets"),
+		"{owner}/{repo}":        filepath.Join("acme", "widgets"),
+		"{host}/{owner}/{repo}": filepath.Join("github.com", "acme", "widgets"),
+		"{owner}-{repo}":        "acme-widgets",
+	}
+	for layout, want := range cases {
+		if got := ExpandLayout(layout, "acme/widgets"); got != want {
+			t.Errorf("ExpandLayout(%q) = %q; want %q", layout, got, want)
+		}
+	}
+}
+
+func TestValidateLayout(t *testing.T) {
+	for _, layout := range []string{"{owner}/{repo}", "{host}/{owner}/{repo}", "src/{repo}"} {
+		if err := ValidateLayout(layout); err != nil {
+			t.Errorf("ValidateLayout(%q) = %v; want nil", layout, err)
+		}
+	}
+	for _, layout := range []string{"{owner}", "/abs/{repo}", "~/{repo}", "../{owner}/{repo}", "{owner}/../{repo}"} {
+		if err := ValidateLayout(layout); err == nil {
+			t.Errorf("ValidateLayout(%q) = nil; want an error", layout)
+		}
+	}
+}
+
+func TestRepositoryFromURL(t *testing.T) {
+	cases := map[string]string{
+		"https://github.com/acme/widgets.git":       "acme/widgets",
+		"https://github.com/acme/widgets":           "acme/widgets",
+		"https://token@github.com/acme/widgets/":    "acme/widgets",
+		"git@github.com:acme/widgets.git":           "acme/widgets",
+		"ssh://git@github.com/acme/widgets.git":     "acme/widgets",
+		"ssh://git@github.com:22/acme/widgets.git":  "acme/widgets",
+		"https://gitlab.com/acme/widgets.git":       "",
+		"git@gitlab.com:acme/widgets.git":           "",
+		"https://github.com/acme":                   "",
+		"https://github.com/acme/widgets/tree/main": "",
+		"/home/user/src/widgets":                    "",
+	}
+	for url, want := range cases {
+		got, ok := RepositoryFromURL(url)
+		if got != want || ok != (want != "") {
+			t.Errorf("RepositoryFromURL(%q) = %q, %t; want %q", url, got, ok, want)
+		}
+	}
+}
+
+func TestIndexClones(t *testing.T) {
+	root := t.TempDir()
+	widgets := filepath.Join(root, "github.com", "acme", "widgets")
+	fork := filepath.Join(root, "forks", "widgets")
+	gadgets := filepath.Join(root, "work", "gadgets")
+	writeClone(t, fork, map[string]string{"origin": "git@github.com:me/widgets.git", "upstream": "git@github.com:acme/widgets.git"})
+	writeClone(t, widgets, map[string]string{"origin": "https://github.com/Acme/Widgets.git"})
+	writeClone(t, gadgets, map[string]string{"origin": "https://github.com/acme/gadgets"})
+	// Hidden directories, clones inside clones and clones too deep are skipped.
+	writeClone(t, filepath.Join(root, ".cache", "tools"), map[string]string{"origin": "https://github.com/acme/tools"})
+	writeClone(t, filepath.Join(gadgets, "vendor", "lib"), map[string]string{"origin": "https://github.com/acme/lib"})
+	writeClone(t, filepath.Join(root, "a", "b", "c", "d", "deep"), map[string]string{"origin": "https://github.com/acme/deep"})
+
+	index := IndexClones([]string{root, filepath.Join(root, "missing")})
+	want := map[string]IndexedClone{
+		"acme/widgets": {Dir: widgets, Root: root},
+		"me/widgets":   {Dir: fork, Root: root},
+		"acme/gadgets": {Dir: gadgets, Root: root},
+	}
+	if !reflect.DeepEqual(index, want) {
+		t.Errorf("IndexClones() = %v; want %v", index, want)
+	}
+}
+
+func TestCheckoutRepoDir(t *testing.T) {
+	base := t.TempDir()
+	root := t.TempDir()
+	override := filepath.Join(t.TempDir(), "widgets")
+	if err := os.MkdirAll(override, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	existing := filepath.Join(base, "github.com", "acme", "gadgets")
+	if err := os.MkdirAll(existing, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	found := filepath.Join(root, "tools")
+	writeClone(t, found, map[string]string{"origin": "https://github.com/acme/tools.git"})
+
+	c := &Checkout{
+		BaseDir: base,
+		Layout:  "{host}/{owner}/{repo}",
+		Paths:   map[string]string{"Acme/Widgets": override},
+		Roots:   []string{root},
+	}
+	cases := []struct {
+		repository string
+		dir        string
+		cloned     bool
+	}{
+		{"acme/widgets", override, true},
+		{"acme/gadgets", existing, true},
+		{"acme/tools", found, true},
+		{"acme/new", filepath.Join(base, "github.com", "acme", "new"), false},
+	}
+	for _, tc := range cases {
+		dir, cloned, err := c.RepoDir(tc.repository)
+		if err != nil || dir != tc.dir || cloned != tc.cloned {
+			t.Errorf("RepoDir(%q) = %q, %t, %v; want %q, %t", tc.repository, dir, cloned, err, tc.dir, tc.cloned)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsafePath is returned for repository names and clone paths that could
+// reach outside the directory they belong in.
+var ErrUnsafePath = errors.New("unsafe path")
+
+var (
+	// ownerPattern matches GitHub user and organization names.
+	ownerPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,38}$`)
+	// repoPattern matches GitHub repository names.
+	repoPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,100}$`)
+)
+
+// ValidateRepository checks that repositoryNameWithOwner is a GitHub
+// owner/repo that is safe to use as path components.
+func ValidateRepository(repositoryNameWithOwner string) error {
+	owner, repo, ok := strings.Cut(repositoryNameWithOwner, "/")
+	switch {
+	case !ok:
+		return fmt.Errorf("%w: repository %q is not owner/repo", ErrUnsafePath, repositoryNameWithOwner)
+	case !ownerPattern.MatchString(owner):
+		return fmt.Errorf("%w: invalid owner %q in repository %q", ErrUnsafePath, owner, repositoryNameWithOwner)
+	case !repoPattern.MatchString(repo) || repo == "." || repo == "..":
+		return fmt.Errorf("%w: invalid name %q in repository %q", ErrUnsafePath, repo, repositoryNameWithOwner)
+	}
+	return nil
+}
+
+// SafeJoin joins the relative path rel below base. It fails when rel is
+// absolute or has ".." components, and when a symlink along the way leads out
+// of base; base itself may be a symlink. Directories that do not exist yet
+// are fine, as they are created when cloning.
+func SafeJoin(base, rel string) (string, error) {
+	if base == "" {
+		return "", fmt.Errorf("%w: no base directory to put %q in", ErrUnsafePath, rel)
+	}
+	if filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" {
+		return "", fmt.Errorf("%w: %q is not relative to %s", ErrUnsafePath, rel, base)
+	}
+	var parts []string
+	for _, part := range strings.FieldsFunc(rel, func(r rune) bool { return r == '/' || r == filepath.Separator }) {
+		switch part {
+		case "..":
+			return "", fmt.Errorf("%w: %q must not contain ..", ErrUnsafePath, rel)
+		case ".":
+		default:
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("%w: %q does not name a directory below %s", ErrUnsafePath, rel, base)
+	}
+
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return "", errors.Wrap(err, "resolving base directory")
+	}
+	root := base
+	if resolved, err := filepath.EvalSymlinks(base); err == nil {
+		root = resolved
+	}
+
+	// Follow the existing part of the path, making sure symlinks stay below root.
+	current := root
+	for i, part := range parts {
+		next := filepath.Join(current, part)
+		info, err := os.Lstat(next)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "checking %s", next)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			resolved, err := filepath.EvalSymlinks(next)
+			if err != nil {
+				return "", fmt.Errorf("%w: %s is a broken symlink", ErrUnsafePath, next)
+			}
+			if !within(root, resolved) {
+				return "", fmt.Errorf("%w: %s links to %s, outside of %s", ErrUnsafePath, next, resolved, base)
+			}
+			next = resolved
+			info, err = os.Stat(next)
+			if err != nil {
+				return "", errors.Wrapf(err, "checking %s", next)
+			}
+		}
+		if !info.IsDir() && i < len(parts)-1 {
+			return "", fmt.Errorf("%w: %s is not a directory", ErrUnsafePath, next)
+		}
+		current = next
+	}
+
+	return filepath.Join(append([]string{base}, parts...)...), nil
+}
+
+// resolvedWithin reports whether path is root or below it once the symlinks
+// of both are resolved.
+func resolvedWithin(root, path string) bool {
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return false
+	}
+	path, err = filepath.EvalSymlinks(path)
+	return err == nil && within(root, path)
+}
+
+// within reports whether path is root or below it.
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateRepository(t *testing.T) {
+	for _, repository := range []string{"acme/widgets", "octo-cat/dot.files", "a/b", "acme_emu/my_repo", "acme/.github"} {
+		if err := ValidateRepository(repository); err != nil {
+			t.Errorf("ValidateRepository(%q) = %v; want nil", repository, err)
+		}
+	}
+
+	malicious := []string{
+		"",
+		"acme",
+		"../widgets",
+		"acme/..",
+		"acme/.",
+		"../../etc/passwd",
+		"acme/../../etc",
+		"acme/widgets/../../x",
+		"/etc/passwd",
+		"acme//widgets",
+		"acme/wid\\gets",
+		"-acme/widgets",
+		"ac me/widgets",
+		"acme/wid\x00gets",
+		"acme/widgets\n",
+		"~/widgets",
+		"acme/" + strings.Repeat("w", 101),
+		strings.Repeat("a", 40) + "/widgets",
+	}
+	for _, repository := range malicious {
+		if err := ValidateRepository(repository); !errors.Is(err, ErrUnsafePath) {
+			t.Errorf("ValidateRepository(%q) = %v; want ErrUnsafePath", repository, err)
+		}
+	}
+}
+
+func TestSafeJoin(t *testing.T) {
+	base := t.TempDir()
+	outside := t.TempDir()
+	mustMkdir := func(dir string) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustSymlink := func(target, link string) {
+		t.Helper()
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks are not supported: %v", err)
+		}
+	}
+	mustMkdir(filepath.Join(base, "acme"))
+	mustMkdir(filepath.Join(base, "real"))
+	mustSymlink(outside, filepath.Join(base, "evil"))
+	mustSymlink(filepath.Join(base, "real"), filepath.Join(base, "alias"))
+	mustSymlink(filepath.Join(base, "missing"), filepath.Join(base, "broken"))
+	mustSymlink("../../..", filepath.Join(base, "acme", "up"))
+	if err := os.WriteFile(filepath.Join(base, "file"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for rel, want := range map[string]string{
+		"acme/widgets":        filepath.Join(base, "acme", "widgets"),
+		"new/owner/repo":      filepath.Join(base, "new", "owner", "repo"),
+		"./acme/./widgets":    filepath.Join(base, "acme", "widgets"),
+		"alias/widgets":       filepath.Join(base, "alias", "widgets"),
+		"acme/widgets/.git/x": filepath.Join(base, "acme", "widgets", ".git", "x"),
+	} {
+		got, err := SafeJoin(base, rel)
+		if err != nil || got != want {
+			t.Errorf("SafeJoin(base, %q) = %q, %v; want %q", rel, got, err, want)
+		}
+	}
+
+	for _, rel := range []string{
+		"",
+		".",
+		"..",
+		"../outside",
+		"acme/../../outside",
+		"acme/widgets/..",
+		"/etc/passwd",
+		"evil/widgets",
+		"evil",
+		"acme/up/etc",
+		"broken/widgets",
+		"file/widgets",
+	} {
+		if got, err := SafeJoin(base, rel); !errors.Is(err, ErrUnsafePath) {
+			t.Errorf("SafeJoin(base, %q) = %q, %v; want ErrUnsafePath", rel, got, err)
+		}
+	}
+
+	if _, err := SafeJoin("", "acme/widgets"); !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("SafeJoin without a base = %v; want ErrUnsafePath", err)
+	}
+
+	// The base directory itself may be a symlink.
+	linkedBase := filepath.Join(outside, "base")
+	mustSymlink(base, linkedBase)
+	if got, err := SafeJoin(linkedBase, "alias/widgets"); err != nil || got != filepath.Join(linkedBase, "alias", "widgets") {
+		t.Errorf("SafeJoin(linked base) = %q, %v", got, err)
+	}
+}
+
+func TestCheckoutRepoDirUnsafe(t *testing.T) {
+	base := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(base, "acme")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+
+	c := &Checkout{BaseDir: base}
+	for _, repository := range []string{"../widgets", "acme/..", "acme/widgets"} {
+		if dir, _, err := c.RepoDir(repository); !errors.Is(err, ErrUnsafePath) {
+			t.Errorf("RepoDir(%q) = %q, %v; want ErrUnsafePath", repository, dir, err)
+		}
+	}
+	if _, err := c.Prepare(t.Context(), "../widgets", 7, &strings.Builder{}); !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("Prepare() = %v; want ErrUnsafePath", err)
+	}
+
+	// Paths must be absolute, and indexed clones must stay in their root even
+	// when they are replaced by a symlink after being indexed.
+	root := t.TempDir()
+	found := filepath.Join(root, "tools")
+	writeClone(t, found, map[string]string{"origin": "https://github.com/acme/tools.git"})
+	c = &Checkout{BaseDir: t.TempDir(), Paths: map[string]string{"acme/gadgets": "code/gadgets"}, Roots: []string{root}}
+	if dir, _, err := c.RepoDir("acme/gadgets"); !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("RepoDir() of a relative path = %q, %v; want ErrUnsafePath", dir, err)
+	}
+	if dir, _, err := c.RepoDir("acme/tools"); err != nil || dir != found {
+		t.Fatalf("RepoDir() = %q, %v; want %q", dir, err, found)
+	}
+	if err := os.RemoveAll(found); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, found); err != nil {
+		t.Fatal(err)
+	}
+	if dir, _, err := c.RepoDir("acme/tools"); !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("RepoDir() of a clone linking out of its root = %q, %v; want ErrUnsafePath", dir, err)
+	}
+
+	// A .worktrees symlink committed to a repository cannot lead out of it.
+	clone := filepath.Join(base, "octo", "widgets")
+	if err := os.MkdirAll(clone, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(clone, WorktreesDir)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WorktreeDir(clone, 7); !errors.Is(err, ErrUnsafePath) {
+		t.Errorf("WorktreeDir() through a symlink = %v; want ErrUnsafePath", err)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -14,123 +13,58 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
-	"github.com/jinwoo1225/gh-rr/internal/model"
+	"github.com/jinwoo1225/gh-rr/internal/cache"
+	"github.com/jinwoo1225/gh-rr/internal/category"
+	"github.com/jinwoo1225/gh-rr/internal/config"
 	"github.com/jinwoo1225/gh-rr/internal/pullrequest"
 	"github.com/jinwoo1225/gh-rr/internal/ui"
 	"github.com/jinwoo1225/gh-rr/internal/utils"
 )
 
-const clearConsoleANSIEscapeCode = "\033c"
-
 func main() {
 	ctx := context.Background()
 
-	var (
-		reviewRequestedPullRequests []*model.GithubPullRequest
-		myPullRequests              []*model.GithubPullRequest
-		draftPullRequests           []*model.GithubPullRequest
-		involvedPullRequest         []*model.GithubPullRequest
-
-		err error
-	)
-
-	wg := sync.WaitGroup{}
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		reviewRequestedPullRequests, err = pullrequest.Fetch(
-			ctx,
-			pullrequest.StateOpen,
-			pullrequest.ReviewRequestedMe,
-			pullrequest.DraftFalse,
-			pullrequest.ArchivedFalse,
-			pullrequest.SortCreated,
-		)
-		if err != nil {
-			log.Println(errors.Wrap(err, "fetching review requested"))
-		}
-	}()
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		myPullRequests, err = pullrequest.Fetch(
-			ctx,
-			pullrequest.StateOpen,
-			pullrequest.AuthorMe,
-			pullrequest.DraftFalse,
-			pullrequest.ArchivedFalse,
-			pullrequest.SortCreated,
-		)
-		if err != nil {
-			log.Println(errors.Wrap(err, "fetching my pull requests"))
-		}
-	}()
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		draftPullRequests, err = pullrequest.Fetch(
-			ctx,
-			pullrequest.StateOpen,
-			pullrequest.DraftTrue,
-			pullrequest.InvolvesMe,
-			pullrequest.ArchivedFalse,
-			pullrequest.SortCreated,
-		)
-		if err != nil {
-			log.Println(errors.Wrap(err, "fetching draft pull requests"))
-		}
-	}()
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		involvedPullRequest, err = pullrequest.Fetch(
-			ctx,
-			pullrequest.StateOpen,
-			pullrequest.InvolvesMe,
-			pullrequest.DraftFalse,
-			pullrequest.ArchivedFalse,
-			pullrequest.SortCreated,
-		)
-		if err != nil {
-			log.Println(errors.Wrap(err, "fetching involved pull requests"))
-		}
-	}()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalln(errors.Wrap(err, "loading config"))
+	}
 
-	wg.Wait()
+	categories := cfg.Categories(category.Registry())
 
-	now := time.Now()
-	revEntries := ui.BuildEntries(reviewRequestedPullRequests, now)
-	myEntries := ui.BuildEntries(myPullRequests, now)
-	draftEntries := ui.BuildEntries(draftPullRequests, now)
-	involvedEntries := ui.BuildEntries(involvedPullRequest, now)
+	fetcher, err := newFetcher(cfg)
+	if err != nil {
+		log.Fatalln(errors.Wrap(err, "creating fetcher"))
+	}
 
-	// Initialize TUI with categories and PR entries
-	categories := []string{"Review Requests", "My PRs", "Draft PRs", "Involved Open PRs"}
-	entries2d := [][]ui.Entry{revEntries, myEntries, draftEntries, involvedEntries}
+	store, err := cache.Open(cache.Path())
+	if err != nil {
+		log.Println(errors.Wrap(err, "ignoring cache"))
+		store = cache.New(cache.Path())
+	}
 
-	// Prepare list Model with initial category
-	initialItems := ui.ItemsFromEntries(entries2d[0])
+	// Fetches start once the TUI is up and are cancelled as soon as it exits
+	fetchCtx, cancelFetch := context.WithCancel(ctx)
+	defer cancelFetch()
 
 	// Customize list delegate for clearer selection
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Foreground(lipgloss.Color("205")).Bold(true)
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Foreground(lipgloss.Color("240"))
 
-	l := list.New(initialItems, &delegate, 0, 0)
+	l := list.New(nil, &delegate, 0, 0)
 	l.SetShowHelp(true)
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
 
 	// Run Bubble Tea program
-	listModel := &ui.ListModel{
-		Categories: categories,
-		Entries:    entries2d,
-		List:       l,
-	}
+	listModel := ui.NewListModel(fetchCtx, categories, l, store, fetcher)
+	listModel.SetCheckouter(&utils.Checkout{
+		BaseDir:  utils.GetBaseDir(),
+		Layout:   cfg.Clone.Layout,
+		Paths:    cfg.Clone.Paths,
+		Roots:    cfg.Clone.Roots,
+		Worktree: cfg.Checkout == config.CheckoutWorktree,
+	})
 	delegate.ShortHelpFunc = func() []key.Binding {
 		now := time.Now()
 		remaining := int(listModel.NextRefresh().Sub(now).Seconds())
@@ -139,12 +73,12 @@ func main() {
 		}
 		timer := utils.HumanizeDuration(remaining)
 		return []key.Binding{
-			ui.Keys.Left, ui.Keys.Right, ui.Keys.Enter,
+			ui.Keys.Left, ui.Keys.Right, ui.Keys.Enter, ui.Keys.Info, ui.Keys.Diff,
 			key.NewBinding(
 				key.WithKeys("r"),
 				key.WithHelp("r", fmt.Sprintf("refresh (in %s)", timer)),
 			),
-			ui.Keys.Checkout, ui.Keys.Quit,
+			ui.Keys.Retry, ui.Keys.Checkout, ui.Keys.Quit,
 		}
 	}
 	delegate.FullHelpFunc = func() [][]key.Binding {
@@ -160,28 +94,27 @@ func main() {
 		)
 		return [][]key.Binding{
 			{ui.Keys.Left, ui.Keys.Right},
-			{ui.Keys.Enter, rBinding, ui.Keys.Checkout},
+			{ui.Keys.Enter, ui.Keys.Info, ui.Keys.Diff, ui.Keys.Timeline, rBinding, ui.Keys.Retry, ui.Keys.Checkout, ui.Keys.Restore},
+			{ui.Keys.Approve, ui.Keys.Comment, ui.Keys.Reject, ui.Keys.Merge},
+			{ui.Keys.Reviewers, ui.Keys.Draft, ui.Keys.Labels, ui.Keys.Assignees, ui.Keys.AssignMe},
 			{ui.Keys.Quit},
 		}
 	}
 
-	finalModel, err := tea.NewProgram(listModel, tea.WithAltScreen()).Run()
-	if err != nil {
+	if _, err := tea.NewProgram(listModel, tea.WithAltScreen()).Run(); err != nil {
 		log.Panicln(errors.Wrap(err, "running tea program"))
 	}
-	m := finalModel.(*ui.ListModel)
+}
 
-	if m.IsQuit() {
-		return
+// newFetcher returns the fetcher selected in the config. In builds with the
+// fixtures tag, setting GH_RR_FIXTURES to a fixture file serves pull requests
+// from it instead of GitHub.
+func newFetcher(cfg *config.Config) (pullrequest.Fetcher, error) {
+	if fetcher, ok, err := fixtureFetcher(); ok {
+		return fetcher, err
 	}
-
-	fmt.Print(clearConsoleANSIEscapeCode)
-
-	selectedEntry := m.Entries[m.CategoryIndex][m.List.Index()]
-	baseDir := utils.GetBaseDir()
-	if m.IsClone() {
-		utils.CloneAndCheckout(ctx, selectedEntry.RepositoryNameWithOwner, selectedEntry.PrNumber, baseDir)
-	} else {
-		utils.OpenURL(selectedEntry.URL)
+	if cfg.Fetcher == config.FetcherSearch {
+		return pullrequest.SearchFetcher{}, nil
 	}
+	return pullrequest.NewGraphQLFetcher()
 }
@@ -0,0 +1,10 @@
+//go:build !fixtures
+
+package main
+
+import "github.com/jinwoo1225/gh-rr/internal/pullrequest"
+
+// fixtureFetcher is only available in builds with the fixtures tag.
+func fixtureFetcher() (pullrequest.Fetcher, bool, error) {
+	return nil, false, nil
+}